@@ -0,0 +1,41 @@
+package uid64
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/Ahmed-Sermani/uid64/internal/bits"
+)
+
+// AnonymizeID pseudonymizes id against casual inspection in logs: it XORs the
+// node ID and sequence with a mask taken from HMAC-SHA256 with key of the
+// ID's timestamp, leaving the timestamp and bit 63 untouched so logs still
+// sort and bucket by creation time. IDs from different milliseconds get
+// different masks. For a given key and timestamp the mapping is a bijection of
+// the low 22 bits, and DeanonymizeID reverses it.
+//
+// It is not encryption. Every ID of a millisecond shares one mask, so anyone
+// who knows a single real ID and its pseudonym can unmask the rest of that
+// millisecond, and IDs remain linkable to their creation time. Do not rely on
+// it to keep node IDs or volumes secret from a determined reader.
+func AnonymizeID(id int64, key []byte) int64 {
+	return id ^ anonymizeMask(id, key)
+}
+
+// DeanonymizeID returns the ID that AnonymizeID turned into anonymized with
+// the same key.
+func DeanonymizeID(anonymized int64, key []byte) int64 {
+	return anonymized ^ anonymizeMask(anonymized, key)
+}
+
+// anonymizeMask derives the XOR mask for the low 22 bits of id from its
+// timestamp, which the mask leaves alone.
+func anonymizeMask(id int64, key []byte) int64 {
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(bits.UnpackTimestamp(id, defaultLayout.Layout)))
+	mac := hmac.New(sha256.New, key)
+	mac.Write(ts[:])
+	sum := mac.Sum(nil)
+	return bits.Low(int64(binary.BigEndian.Uint64(sum)), defaultLayout.Layout)
+}
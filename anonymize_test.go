@@ -0,0 +1,36 @@
+package uid64_test
+
+import (
+	"testing"
+	"testing/quick"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestAnonymizeID(t *testing.T) {
+	key := []byte("log pseudonymization key")
+	if err := quick.Check(func(id int64) bool {
+		anon := uid64.AnonymizeID(id, key)
+		return anon>>22 == id>>22 && uid64.DeanonymizeID(anon, key) == id
+	}, nil); err != nil {
+		t.Error(err)
+	}
+
+	// Within a millisecond every ID is XORed with the same mask, so the
+	// mapping is a bijection of the low 22 bits.
+	const ts = int64(123456789) << 22
+	if err := quick.Check(func(a, b uint32) bool {
+		x, y := ts|int64(a)&(1<<22-1), ts|int64(b)&(1<<22-1)
+		return uid64.AnonymizeID(x, key)^uid64.AnonymizeID(y, key) == x^y
+	}, nil); err != nil {
+		t.Error(err)
+	}
+
+	id := ts | 42<<12 | 7
+	if uid64.AnonymizeID(id, key) == id {
+		t.Error("AnonymizeID left the ID unchanged")
+	}
+	if uid64.AnonymizeID(id, key) == uid64.AnonymizeID(id, []byte("another key")) {
+		t.Error("AnonymizeID gave the same result for two keys")
+	}
+}
@@ -0,0 +1,51 @@
+package uid64
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var ErrInvalidLength = errors.New("binary ID must be 8 bytes")
+
+// ToBytes returns id as 8 big-endian bytes, so that for non-negative IDs
+// comparing the slices with bytes.Compare orders them like the IDs.
+func ToBytes(id int64) []byte {
+	return AppendBytes(make([]byte, 0, 8), id)
+}
+
+// AppendBytes appends the 8 bytes of ToBytes(id) to dst and returns the
+// extended slice, allocating only if dst lacks the capacity.
+func AppendBytes(dst []byte, id int64) []byte {
+	u := uint64(id)
+	return append(dst, byte(u>>56), byte(u>>48), byte(u>>40), byte(u>>32), byte(u>>24), byte(u>>16), byte(u>>8), byte(u))
+}
+
+// FromBytes decodes bytes produced by ToBytes. It returns ErrInvalidLength
+// unless b is exactly 8 bytes long.
+func FromBytes(b []byte) (int64, error) {
+	if len(b) != 8 {
+		return 0, ErrInvalidLength
+	}
+	return int64(binary.BigEndian.Uint64(b)), nil
+}
+
+// Packed128 returns a 16 byte key holding id in the first 8 bytes and extra,
+// an application payload such as a customer ID, in the last 8, both
+// big-endian. For non-negative IDs such keys sort with bytes.Compare by ID
+// first and payload second.
+func Packed128(id int64, extra uint64) [16]byte {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], uint64(id))
+	binary.BigEndian.PutUint64(b[8:], extra)
+	return b
+}
+
+// UnpackID returns the ID stored in b by Packed128.
+func UnpackID(b [16]byte) int64 {
+	return int64(binary.BigEndian.Uint64(b[:8]))
+}
+
+// UnpackExtra returns the payload stored in b by Packed128.
+func UnpackExtra(b [16]byte) uint64 {
+	return binary.BigEndian.Uint64(b[8:])
+}
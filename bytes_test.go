@@ -0,0 +1,71 @@
+package uid64_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"testing/quick"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestBytes(t *testing.T) {
+	if got := uid64.ToBytes(0x0102030405060708); !bytes.Equal(got, []byte{1, 2, 3, 4, 5, 6, 7, 8}) {
+		t.Errorf("ToBytes = %v, want [1 2 3 4 5 6 7 8]", got)
+	}
+	roundTrip := func(id int64) bool {
+		got, err := uid64.FromBytes(uid64.ToBytes(id))
+		return err == nil && got == id
+	}
+	if err := quick.Check(roundTrip, nil); err != nil {
+		t.Error(err)
+	}
+	ordered := func(a, b int64) bool {
+		a, b = a&math.MaxInt64, b&math.MaxInt64
+		return (a < b) == (bytes.Compare(uid64.ToBytes(a), uid64.ToBytes(b)) < 0)
+	}
+	if err := quick.Check(ordered, nil); err != nil {
+		t.Error(err)
+	}
+
+	for _, b := range [][]byte{nil, make([]byte, 7), make([]byte, 9)} {
+		if _, err := uid64.FromBytes(b); err != uid64.ErrInvalidLength {
+			t.Errorf("FromBytes(%d bytes): err = %v, want ErrInvalidLength", len(b), err)
+		}
+	}
+}
+
+func TestAppendBytes(t *testing.T) {
+	buf := make([]byte, 0, 16)
+	buf = uid64.AppendBytes(buf, 1)
+	buf = uid64.AppendBytes(buf, 2)
+	if len(buf) != 16 || buf[7] != 1 || buf[15] != 2 {
+		t.Errorf("AppendBytes = %v", buf)
+	}
+	if n := testing.AllocsPerRun(100, func() { uid64.AppendBytes(buf[:0], 42) }); n != 0 {
+		t.Errorf("AppendBytes allocated %v times, want 0", n)
+	}
+}
+
+func TestPacked128(t *testing.T) {
+	b := uid64.Packed128(0x0102030405060708, 0x090a0b0c0d0e0f10)
+	if want := [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}; b != want {
+		t.Errorf("Packed128 = %v, want %v", b, want)
+	}
+	roundTrip := func(id int64, extra uint64) bool {
+		b := uid64.Packed128(id, extra)
+		return uid64.UnpackID(b) == id && uid64.UnpackExtra(b) == extra
+	}
+	if err := quick.Check(roundTrip, nil); err != nil {
+		t.Error(err)
+	}
+	ordered := func(a, b int64, x, y uint64) bool {
+		a, b = a&math.MaxInt64, b&math.MaxInt64
+		pa, pb := uid64.Packed128(a, x), uid64.Packed128(b, y)
+		want := a < b || a == b && x < y
+		return want == (bytes.Compare(pa[:], pb[:]) < 0)
+	}
+	if err := quick.Check(ordered, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,26 @@
+package uid64
+
+import (
+	"math"
+	"math/bits"
+)
+
+// IDWithChecksum returns id with bit 63 set or cleared so that the 64 bit word
+// has an even number of set bits. VerifyChecksum then detects any single bit
+// flipped in storage or transmission. About half of all checksummed IDs are
+// negative, with the consequences described at WithTypeTag; the parity bit
+// takes the place of the type tag and of EmbedVersion, so it cannot be
+// combined with either. Clear bit 63 to recover the plain ID.
+func IDWithChecksum(id int64) int64 {
+	id &^= math.MinInt64
+	if bits.OnesCount64(uint64(id))%2 == 1 {
+		id |= math.MinInt64
+	}
+	return id
+}
+
+// VerifyChecksum reports whether id has even parity, as every ID returned by
+// IDWithChecksum does.
+func VerifyChecksum(id int64) bool {
+	return bits.OnesCount64(uint64(id))%2 == 0
+}
@@ -0,0 +1,38 @@
+package uid64_test
+
+import (
+	"math"
+	"testing"
+	"testing/quick"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestIDWithChecksum(t *testing.T) {
+	for _, tc := range []struct{ id, want int64 }{
+		{0, 0},
+		{1, math.MinInt64 | 1},
+		{3, 3},
+		{math.MaxInt64, math.MinInt64 | math.MaxInt64},
+	} {
+		got := uid64.IDWithChecksum(tc.id)
+		if got != tc.want {
+			t.Errorf("IDWithChecksum(%d) = %d, want %d", tc.id, got, tc.want)
+		}
+		if !uid64.VerifyChecksum(got) {
+			t.Errorf("VerifyChecksum(IDWithChecksum(%d)) = false", tc.id)
+		}
+		if got&math.MaxInt64 != tc.id {
+			t.Errorf("IDWithChecksum(%d) changed the low 63 bits", tc.id)
+		}
+	}
+}
+
+func TestVerifyChecksumDetectsBitFlips(t *testing.T) {
+	if err := quick.Check(func(id int64, bit uint8) bool {
+		sum := uid64.IDWithChecksum(id & math.MaxInt64)
+		return uid64.VerifyChecksum(sum) && !uid64.VerifyChecksum(sum^1<<(bit%64))
+	}, &quick.Config{MaxCount: 10000}); err != nil {
+		t.Error(err)
+	}
+}
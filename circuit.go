@@ -0,0 +1,177 @@
+package uid64
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrCircuitOpen           = errors.New("circuit breaker is open after repeated clock rollbacks")
+	ErrInvalidCircuitBreaker = errors.New("circuit breaker needs a positive rollback count, window and cooldown")
+)
+
+// CircuitState is the state of a generator's circuit breaker.
+type CircuitState uint32
+
+const (
+	// CircuitClosed lets IDs through. It is the state of a generator without
+	// a circuit breaker.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen refuses all IDs with ErrCircuitOpen until the cooldown ends.
+	CircuitOpen
+	// CircuitHalfOpen lets IDs through on trial after the cooldown: the next
+	// ID produced without a clock rollback closes the circuit, and the next
+	// rollback opens it again.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", uint32(s))
+	}
+}
+
+// WithCircuitBreaker makes the generator stop issuing IDs once the clock has
+// been seen going backwards maxRollbacks times within window. It then fails
+// every call with ErrCircuitOpen for a cooldown, which is window unless set
+// by WithCircuitCooldown, before letting IDs through on trial.
+func WithCircuitBreaker(maxRollbacks int, window time.Duration) GeneratorOption {
+	return func(g *Generator) error {
+		g.circuit.maxRollbacks = maxRollbacks
+		g.circuit.window = window
+		return nil
+	}
+}
+
+// WithCircuitCooldown sets how long the circuit breaker set by
+// WithCircuitBreaker stays open once tripped.
+func WithCircuitCooldown(cooldown time.Duration) GeneratorOption {
+	return func(g *Generator) error {
+		g.circuit.cooldown = cooldown
+		return nil
+	}
+}
+
+// CircuitState returns the state of g's circuit breaker, which is always
+// CircuitClosed if g has none.
+func (g *Generator) CircuitState() CircuitState {
+	if g.breaker == nil {
+		return CircuitClosed
+	}
+	return g.breaker.currentState(time.Now())
+}
+
+type circuitConfig struct {
+	maxRollbacks int
+	window       time.Duration
+	cooldown     time.Duration
+}
+
+// newBreaker returns the breaker configured by c, or nil if none was.
+func (c circuitConfig) newBreaker() (*circuitBreaker, error) {
+	if c.maxRollbacks == 0 && c.window == 0 {
+		return nil, nil
+	}
+	if c.cooldown == 0 {
+		c.cooldown = c.window
+	}
+	if c.maxRollbacks < 1 || c.window <= 0 || c.cooldown < 0 {
+		return nil, ErrInvalidCircuitBreaker
+	}
+	return &circuitBreaker{config: c}, nil
+}
+
+// circuitBreaker keeps its state in an atomic word so that NextID only takes
+// the lock while the circuit is not closed or when a rollback is recorded.
+// Times are read from the wall clock, not the generator's clock, since the
+// latter is the one misbehaving.
+type circuitBreaker struct {
+	state  uint32
+	config circuitConfig
+
+	mu sync.Mutex
+	// rollbacks holds the times of the rollbacks seen within the window, the
+	// oldest first.
+	rollbacks []time.Time
+	openedAt  time.Time
+}
+
+// allow reports ErrCircuitOpen if the circuit is open, moving it to half-open
+// once the cooldown is over.
+func (b *circuitBreaker) allow() error {
+	if b == nil || CircuitState(atomic.LoadUint32(&b.state)) == CircuitClosed {
+		return nil
+	}
+	if b.currentState(time.Now()) == CircuitOpen {
+		return ErrCircuitOpen
+	}
+	return nil
+}
+
+func (b *circuitBreaker) currentState(now time.Time) CircuitState {
+	state := CircuitState(atomic.LoadUint32(&b.state))
+	if state != CircuitOpen {
+		return state
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if CircuitState(b.state) == CircuitOpen && now.Sub(b.openedAt) >= b.config.cooldown {
+		atomic.StoreUint32(&b.state, uint32(CircuitHalfOpen))
+	}
+	return CircuitState(b.state)
+}
+
+// succeed closes a half-open circuit after an ID is produced.
+func (b *circuitBreaker) succeed() {
+	if b == nil || CircuitState(atomic.LoadUint32(&b.state)) != CircuitHalfOpen {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if CircuitState(b.state) == CircuitHalfOpen {
+		b.rollbacks = b.rollbacks[:0]
+		atomic.StoreUint32(&b.state, uint32(CircuitClosed))
+	}
+}
+
+// recordRollback counts a clock rollback, opening the circuit if it is the
+// last straw or if the circuit is half-open.
+func (b *circuitBreaker) recordRollback() {
+	if b == nil {
+		return
+	}
+	now := time.Now()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch CircuitState(b.state) {
+	case CircuitOpen:
+		return
+	case CircuitHalfOpen:
+		b.open(now)
+		return
+	}
+	i := 0
+	for i < len(b.rollbacks) && now.Sub(b.rollbacks[i]) >= b.config.window {
+		i++
+	}
+	b.rollbacks = append(b.rollbacks[i:], now)
+	if len(b.rollbacks) >= b.config.maxRollbacks {
+		b.open(now)
+	}
+}
+
+func (b *circuitBreaker) open(now time.Time) {
+	b.openedAt = now
+	b.rollbacks = nil
+	atomic.StoreUint32(&b.state, uint32(CircuitOpen))
+}
@@ -0,0 +1,85 @@
+package uid64_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestWithCircuitBreaker(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	gen, err := uid64.NewWithOptions(
+		uid64.WithNodeID(1),
+		uid64.WithClock(clock.Now),
+		uid64.WithCircuitBreaker(2, time.Minute),
+		uid64.WithCircuitCooldown(50*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+
+	rollback := func() {
+		t.Helper()
+		clock.now--
+		if _, err := gen.NextID(); !errors.Is(err, uid64.ErrInvalidState) {
+			t.Fatalf("NextID after a rollback: err = %v, want ErrInvalidState", err)
+		}
+		clock.now++
+	}
+
+	if _, err := gen.NextID(); err != nil {
+		t.Fatal(err)
+	}
+	rollback()
+	if got := gen.CircuitState(); got != uid64.CircuitClosed {
+		t.Fatalf("CircuitState after one rollback = %v, want closed", got)
+	}
+	rollback()
+	if got := gen.CircuitState(); got != uid64.CircuitOpen {
+		t.Fatalf("CircuitState after two rollbacks = %v, want open", got)
+	}
+	if _, err := gen.NextID(); err != uid64.ErrCircuitOpen {
+		t.Errorf("NextID with the circuit open: err = %v, want ErrCircuitOpen", err)
+	}
+	if _, err := gen.NextIDBatch(2); err != uid64.ErrCircuitOpen {
+		t.Errorf("NextIDBatch with the circuit open: err = %v, want ErrCircuitOpen", err)
+	}
+
+	// A rollback on trial opens the circuit again.
+	time.Sleep(60 * time.Millisecond)
+	if got := gen.CircuitState(); got != uid64.CircuitHalfOpen {
+		t.Fatalf("CircuitState after the cooldown = %v, want half-open", got)
+	}
+	rollback()
+	if got := gen.CircuitState(); got != uid64.CircuitOpen {
+		t.Fatalf("CircuitState after a half-open rollback = %v, want open", got)
+	}
+
+	// A good ID on trial closes it.
+	time.Sleep(60 * time.Millisecond)
+	clock.now++
+	if _, err := gen.NextID(); err != nil {
+		t.Fatal(err)
+	}
+	if got := gen.CircuitState(); got != uid64.CircuitClosed {
+		t.Errorf("CircuitState after a half-open success = %v, want closed", got)
+	}
+}
+
+func TestWithCircuitBreakerErrors(t *testing.T) {
+	for _, opts := range [][]uid64.GeneratorOption{
+		{uid64.WithCircuitBreaker(0, time.Second)},
+		{uid64.WithCircuitBreaker(3, 0)},
+		{uid64.WithCircuitBreaker(3, time.Second), uid64.WithCircuitCooldown(-time.Second)},
+	} {
+		if _, err := uid64.NewWithOptions(opts...); err != uid64.ErrInvalidCircuitBreaker {
+			t.Errorf("err = %v, want ErrInvalidCircuitBreaker", err)
+		}
+	}
+	if got := g.CircuitState(); got != uid64.CircuitClosed {
+		t.Errorf("CircuitState without a breaker = %v, want closed", got)
+	}
+}
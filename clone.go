@@ -0,0 +1,40 @@
+package uid64
+
+import "sync/atomic"
+
+// Clone returns a generator with g's node ID and configuration but state of
+// its own, as if freshly constructed. It is meant for isolating tests that
+// share a setup. Two generators with the same node ID issue duplicate IDs
+// whenever they are used in the same millisecond, so never use a clone
+// alongside g in production. Clones stay out of the node ID registry, which
+// would otherwise refuse them.
+func (g *Generator) Clone() *Generator {
+	c := &Generator{
+		strategy:        g.strategy,
+		epoch:           g.epoch,
+		clock:           g.clock,
+		waitStrategy:    g.waitStrategy,
+		statsHook:       g.statsHook,
+		layout:          g.layout,
+		datacenterBits:  g.datacenterBits,
+		workerBits:      g.workerBits,
+		maxBatchSize:    g.maxBatchSize,
+		typeTag:         g.typeTag,
+		initialSequence: g.initialSequence,
+		driftTolerance:  g.driftTolerance,
+		expiryThreshold: g.expiryThreshold,
+		expiryHook:      g.expiryHook,
+		floodLimit:      g.floodLimit,
+		circuit:         g.circuit,
+		unregistered:    true,
+	}
+	c.breaker, _ = g.circuit.newBreaker()
+	if g.limiter != nil {
+		c.limiter = newFloodLimiter(g.floodLimit)
+	}
+	if atomic.LoadUint32(&g.nodeIDResolved) == 1 {
+		c.nodeID = g.nodeID
+		c.nodeIDResolved = 1
+	}
+	return c
+}
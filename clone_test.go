@@ -0,0 +1,39 @@
+package uid64_test
+
+import (
+	"testing"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestClone(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	gen, err := uid64.NewWithOptions(uid64.WithNodeID(1), uid64.WithClock(clock.Now), uid64.WithBitLayout(6, 16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+	for i := 0; i < 5; i++ {
+		if _, err := gen.NextID(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	clone := gen.Clone()
+	if got := clone.LastTimestamp(); got != -1 {
+		t.Errorf("clone LastTimestamp = %d, want -1", got)
+	}
+	id, err := clone.NextInt64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := gen.Decompose(id); c.NodeID != 1 || c.Timestamp != 1000 || c.Sequence != 0 {
+		t.Errorf("Decompose = %+v, want node 1 timestamp 1000 sequence 0", c)
+	}
+	if got := clone.Stats().TotalGenerated; got != 1 {
+		t.Errorf("clone TotalGenerated = %d, want 1", got)
+	}
+	if got := gen.Stats().TotalGenerated; got != 5 {
+		t.Errorf("original TotalGenerated = %d, want 5", got)
+	}
+}
@@ -0,0 +1,79 @@
+package uid64
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// debugTimeLayout is RFC 3339 with milliseconds, the precision of an ID.
+const debugTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+var ErrInvalidDebugString = errors.New("invalid uid64 debug string")
+
+// DebugString describes id for logs and debugging in the form
+//
+//	uid64{ts=2024-03-15T10:23:45.123Z node=42 seq=7 hex=0x112233445566}
+//
+// assuming the default epoch and bit layout.
+func DebugString(id int64) string {
+	c := Decompose(id)
+	return fmt.Sprintf("uid64{ts=%s node=%d seq=%d hex=%#x}", c.Time.Format(debugTimeLayout), c.NodeID, c.Sequence, uint64(id))
+}
+
+// ParseDebugString returns the ID described by a string from DebugString. The
+// hex field is authoritative; if it is left out, the ID is rebuilt from the
+// other fields, so a hand-written uid64{ts=... node=... seq=...} works too.
+// Fields that contradict the hex field yield ErrInvalidDebugString.
+func ParseDebugString(s string) (int64, error) {
+	if !strings.HasPrefix(s, "uid64{") || !strings.HasSuffix(s, "}") {
+		return 0, ErrInvalidDebugString
+	}
+	var (
+		c      IDComponents
+		id     int64
+		hasHex bool
+		seen   = make(map[string]bool)
+	)
+	for _, field := range strings.Fields(s[len("uid64{") : len(s)-1]) {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 || seen[kv[0]] {
+			return 0, ErrInvalidDebugString
+		}
+		seen[kv[0]] = true
+		var err error
+		switch kv[0] {
+		case "ts":
+			c.Time, err = time.Parse(debugTimeLayout, kv[1])
+		case "node":
+			c.NodeID, err = strconv.Atoi(kv[1])
+		case "seq":
+			c.Sequence, err = strconv.ParseInt(kv[1], 10, 64)
+		case "hex":
+			var u uint64
+			u, err = strconv.ParseUint(kv[1], 0, 64)
+			id, hasHex = int64(u), true
+		default:
+			return 0, ErrInvalidDebugString
+		}
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s: %v", ErrInvalidDebugString, kv[0], err)
+		}
+	}
+
+	if !hasHex {
+		ts := unixMilli(c.Time) - customEpoch
+		if len(seen) != 3 || ts < 0 || ts > maxTimestamp ||
+			c.NodeID < 0 || c.NodeID > maxNodeID || c.Sequence < 0 || c.Sequence > int64(maxSequence) {
+			return 0, ErrInvalidDebugString
+		}
+		return defaultLayout.compose(ts, c.NodeID, c.Sequence), nil
+	}
+	d := Decompose(id)
+	if seen["ts"] && !c.Time.Equal(d.Time) || seen["node"] && c.NodeID != d.NodeID || seen["seq"] && c.Sequence != d.Sequence {
+		return 0, ErrInvalidDebugString
+	}
+	return id, nil
+}
@@ -0,0 +1,48 @@
+package uid64_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestDebugString(t *testing.T) {
+	ts := time.Date(2024, 3, 15, 10, 23, 45, 123e6, time.UTC)
+	id, err := uid64.ParseDebugString("uid64{ts=2024-03-15T10:23:45.123Z node=42 seq=7}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := uid64.Decompose(id); !c.Time.Equal(ts) || c.NodeID != 42 || c.Sequence != 7 {
+		t.Fatalf("Decompose = %+v, want %v node 42 seq 7", c, ts)
+	}
+
+	s := uid64.DebugString(id)
+	want := "uid64{ts=2024-03-15T10:23:45.123Z node=42 seq=7 hex=" + "0x" + strings.TrimLeft(uid64.EncodeHex(id), "0") + "}"
+	if s != want {
+		t.Errorf("DebugString = %q, want %q", s, want)
+	}
+	if got, err := uid64.ParseDebugString(s); err != nil || got != id {
+		t.Errorf("ParseDebugString(%q) = %d, %v, want %d", s, got, err, id)
+	}
+}
+
+func TestParseDebugStringErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"uid64{}",
+		"uid64{ts=2024-03-15T10:23:45.123Z node=42}",
+		"uid64{ts=2024-03-15T10:23:45.123Z node=1024 seq=7}",
+		"uid64{ts=yesterday node=42 seq=7}",
+		"uid64{hex=0x1 hex=0x1}",
+		"uid64{hex=0x1 color=red}",
+		"uid64{node=1 hex=0x1}",
+		"id{hex=0x1}",
+	} {
+		if _, err := uid64.ParseDebugString(s); !errors.Is(err, uid64.ErrInvalidDebugString) {
+			t.Errorf("ParseDebugString(%q): err = %v, want ErrInvalidDebugString", s, err)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package uid64
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Ahmed-Sermani/uid64/internal/bits"
+)
+
+var (
+	ErrOutOfBoundTimestamp = errors.New("timestamp does not fit in the timestamp bits")
+	ErrOutOfBoundSequence  = errors.New("sequence does not fit in the sequence bits")
+)
+
+// IDComponents holds the fields packed into an ID by NextID.
+type IDComponents struct {
+	// Timestamp is the number of milliseconds since the custom epoch.
+	Timestamp int64
+	// Time is the absolute creation time in UTC.
+	Time     time.Time
+	NodeID   int
+	Sequence int64
+
+	// DatacenterID and WorkerID split NodeID when the generator was
+	// configured with WithDatacenterLayout.
+	DatacenterID int
+	WorkerID     int
+}
+
+// Decompose splits id into the timestamp, node ID and sequence it was built
+// from, assuming the default epoch.
+func Decompose(id int64) IDComponents {
+	return decompose(id, customEpoch)
+}
+
+// NewFromComponents builds the ID that NextID would have returned for the
+// given timestamp, in milliseconds since the default epoch, node ID and
+// sequence, as when replaying an audit log that recorded them separately. It
+// is the inverse of Decompose and fails with ErrOutOfBoundTimestamp,
+// ErrOutOfBoundNodeID or ErrOutOfBoundSequence if a component does not fit
+// in its field.
+func NewFromComponents(timestamp int64, nodeID int, sequence int64) (int64, error) {
+	l := defaultLayout
+	switch {
+	case timestamp < 0 || timestamp > maxTimestamp:
+		return 0, ErrOutOfBoundTimestamp
+	case nodeID < 0 || nodeID > l.maxNodeID:
+		return 0, ErrOutOfBoundNodeID
+	case sequence < 0 || sequence > l.maxSequence:
+		return 0, ErrOutOfBoundSequence
+	}
+	return l.compose(timestamp, nodeID, sequence), nil
+}
+
+// DecomposeAll is like Decompose for each of ids, returning the components
+// in the same order. It allocates the result once and decodes in a single
+// loop, which is cheaper than calling Decompose per ID on large batches.
+func DecomposeAll(ids []int64) []IDComponents {
+	l := bits.Layout{NodeIDBits: nodeIDBits, SequenceBits: sequenceBits}
+	out := make([]IDComponents, len(ids))
+	for i, id := range ids {
+		ts := bits.UnpackTimestamp(id, l)
+		out[i] = IDComponents{
+			Timestamp: ts,
+			Time:      epochTime(ts, customEpoch),
+			NodeID:    bits.UnpackNodeID(id, l),
+			Sequence:  bits.UnpackSequence(id, l),
+		}
+	}
+	return out
+}
+
+// DecomposeWithEpoch is like Decompose for IDs from a generator configured
+// with WithEpoch(epoch).
+func DecomposeWithEpoch(id int64, epoch time.Time) IDComponents {
+	return decompose(id, unixMilli(epoch))
+}
+
+// DefaultEpoch returns the epoch of generators not configured with WithEpoch,
+// 2015-01-01 UTC.
+func DefaultEpoch() time.Time {
+	return epochTime(0, customEpoch)
+}
+
+// Epoch returns the instant g's timestamps count from, in UTC.
+func (g *Generator) Epoch() time.Time {
+	return epochTime(0, g.epoch)
+}
+
+// Decompose is like the package-level Decompose but uses the epoch and bit
+// layout of g.
+func (g *Generator) Decompose(id int64) IDComponents {
+	return decomposeLayout(id, g.epoch, g.layout)
+}
+
+// NodeIDOf returns the node ID embedded in id.
+func NodeIDOf(id int64) int {
+	return defaultLayout.nodeID(id)
+}
+
+// SequenceOf returns the sequence number embedded in id.
+func SequenceOf(id int64) int64 {
+	return defaultLayout.sequence(id)
+}
+
+// TimeOf returns the creation time embedded in id in UTC, assuming the default
+// epoch.
+func TimeOf(id int64) time.Time {
+	return epochTime(defaultLayout.timestamp(id), customEpoch)
+}
+
+// Diff returns the time between the creation of a and b: positive if a is the
+// older ID, negative if it is the newer one.
+func Diff(a, b int64) time.Duration {
+	return time.Duration(defaultLayout.timestamp(b)-defaultLayout.timestamp(a)) * time.Millisecond
+}
+
+// SameMillisecond reports whether a and b carry the same timestamp.
+func SameMillisecond(a, b int64) bool {
+	return defaultLayout.timestamp(a) == defaultLayout.timestamp(b)
+}
+
+// TimeBoundCheck reports whether id carries a creation time at or after
+// since, at millisecond precision, assuming the default epoch, as in checking
+// whether an ID was issued in the last five minutes. It looks only at the
+// timestamp embedded in id, so it says nothing about whether any generator
+// actually issued id: a forged or corrupt ID passes if its timestamp does.
+func TimeBoundCheck(id int64, since time.Time) bool {
+	return defaultLayout.timestamp(id) >= unixMilli(since)-customEpoch
+}
+
+func decompose(id int64, epoch int64) IDComponents {
+	return decomposeLayout(id, epoch, defaultLayout)
+}
+
+func decomposeLayout(id int64, epoch int64, l layout) IDComponents {
+	ts := l.timestamp(id)
+	c := IDComponents{
+		Timestamp: ts,
+		Time:      epochTime(ts, epoch),
+		NodeID:    l.nodeID(id),
+		Sequence:  l.sequence(id),
+	}
+	if l.hasDatacenter() {
+		c.DatacenterID, c.WorkerID = l.splitNodeID(c.NodeID)
+	}
+	return c
+}
+
+func epochTime(ts int64, epoch int64) time.Time {
+	return time.Unix(0, (ts+epoch)*int64(time.Millisecond)).UTC()
+}
+
+func unixMilli(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,203 @@
+package uid64_test
+
+import (
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestDecompose(t *testing.T) {
+	gen, err := uid64.NewWithNodeID(42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+	before := time.Now().Truncate(time.Millisecond)
+	id, err := gen.NextInt64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	after := time.Now()
+
+	c := uid64.Decompose(id)
+	if c.NodeID != 42 {
+		t.Errorf("NodeID = %d, want 42", c.NodeID)
+	}
+	if c.Sequence != 0 {
+		t.Errorf("Sequence = %d, want 0", c.Sequence)
+	}
+	if c.Time.Before(before) || c.Time.After(after) {
+		t.Errorf("Time = %v, want between %v and %v", c.Time, before, after)
+	}
+	if c.Time.Location() != time.UTC {
+		t.Errorf("Time location = %v, want UTC", c.Time.Location())
+	}
+	if got := uid64.NodeIDOf(id); got != c.NodeID {
+		t.Errorf("NodeIDOf = %d, want %d", got, c.NodeID)
+	}
+	if got := uid64.SequenceOf(id); got != c.Sequence {
+		t.Errorf("SequenceOf = %d, want %d", got, c.Sequence)
+	}
+	if got := uid64.TimeOf(id); !got.Equal(c.Time) {
+		t.Errorf("TimeOf = %v, want %v", got, c.Time)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	gen := newTestGenerator(t, clock.Now)
+	older, err := gen.NextInt64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	same, err := gen.NextInt64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock.now += 1500
+	newer, err := gen.NextInt64()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := uid64.Diff(older, newer); got != 1500*time.Millisecond {
+		t.Errorf("Diff(older, newer) = %v, want 1.5s", got)
+	}
+	if got := uid64.Diff(newer, older); got != -1500*time.Millisecond {
+		t.Errorf("Diff(newer, older) = %v, want -1.5s", got)
+	}
+	if got := uid64.Diff(older, same); got != 0 {
+		t.Errorf("Diff(older, same) = %v, want 0", got)
+	}
+	if !uid64.SameMillisecond(older, same) {
+		t.Error("SameMillisecond(older, same) = false")
+	}
+	if uid64.SameMillisecond(older, newer) {
+		t.Error("SameMillisecond(older, newer) = true")
+	}
+}
+
+func TestDecomposeAll(t *testing.T) {
+	ids, err := g.NextIDBatch(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids = append(ids, 0, -1, 1<<63-1)
+	got := uid64.DecomposeAll(ids)
+	if len(got) != len(ids) {
+		t.Fatalf("len(DecomposeAll) = %d, want %d", len(got), len(ids))
+	}
+	for i, id := range ids {
+		if want := uid64.Decompose(id); got[i] != want {
+			t.Errorf("DecomposeAll[%d] = %+v, want %+v", i, got[i], want)
+		}
+	}
+	if got := uid64.DecomposeAll(nil); len(got) != 0 {
+		t.Errorf("DecomposeAll(nil) = %v, want empty", got)
+	}
+}
+
+func decomposeBenchIDs(b *testing.B) []int64 {
+	ids := make([]int64, 10000)
+	for i := range ids {
+		ids[i] = int64(i)<<22 | int64(i%1024)<<12 | int64(i%4096)
+	}
+	b.ResetTimer()
+	b.ReportAllocs()
+	return ids
+}
+
+func BenchmarkDecomposeLoop(b *testing.B) {
+	ids := decomposeBenchIDs(b)
+	for i := 0; i < b.N; i++ {
+		out := make([]uid64.IDComponents, len(ids))
+		for j, id := range ids {
+			out[j] = uid64.Decompose(id)
+		}
+	}
+}
+
+func BenchmarkDecomposeAll(b *testing.B) {
+	ids := decomposeBenchIDs(b)
+	for i := 0; i < b.N; i++ {
+		uid64.DecomposeAll(ids)
+	}
+}
+
+func TestEpoch(t *testing.T) {
+	want := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+	if got := uid64.DefaultEpoch(); !got.Equal(want) || got.Location() != time.UTC {
+		t.Errorf("DefaultEpoch = %v, want %v", got, want)
+	}
+	if got := g.Epoch(); !got.Equal(want) {
+		t.Errorf("Epoch of a default generator = %v, want %v", got, want)
+	}
+
+	epoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60))
+	gen, err := uid64.NewWithOptions(uid64.WithNodeID(1), uid64.WithEpoch(epoch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+	if got := gen.Epoch(); !got.Equal(epoch) || got.Location() != time.UTC {
+		t.Errorf("Epoch = %v, want %v in UTC", got, epoch)
+	}
+}
+
+func TestNewFromComponents(t *testing.T) {
+	roundTrip := func(ts int64, nodeID uint16, seq uint16) bool {
+		ts &= 1<<41 - 1
+		n, s := int(nodeID)%(uid64.MaxNodeID()+1), int64(seq)%int64(uid64.MaxSequence()+1)
+		id, err := uid64.NewFromComponents(ts, n, s)
+		if err != nil {
+			return false
+		}
+		c := uid64.Decompose(id)
+		return c.Timestamp == ts && c.NodeID == n && c.Sequence == s
+	}
+	if err := quick.Check(roundTrip, nil); err != nil {
+		t.Error(err)
+	}
+
+	for _, tc := range []struct {
+		ts     int64
+		nodeID int
+		seq    int64
+		want   error
+	}{
+		{-1, 0, 0, uid64.ErrOutOfBoundTimestamp},
+		{1 << 41, 0, 0, uid64.ErrOutOfBoundTimestamp},
+		{0, -1, 0, uid64.ErrOutOfBoundNodeID},
+		{0, 1024, 0, uid64.ErrOutOfBoundNodeID},
+		{0, 0, -1, uid64.ErrOutOfBoundSequence},
+		{0, 0, 4096, uid64.ErrOutOfBoundSequence},
+	} {
+		if _, err := uid64.NewFromComponents(tc.ts, tc.nodeID, tc.seq); err != tc.want {
+			t.Errorf("NewFromComponents(%d, %d, %d): err = %v, want %v", tc.ts, tc.nodeID, tc.seq, err, tc.want)
+		}
+	}
+}
+
+func TestTimeBoundCheck(t *testing.T) {
+	id, err := uid64.NewFromComponents(1<<40, 3, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := uid64.TimeOf(id)
+	for _, tc := range []struct {
+		since time.Time
+		want  bool
+	}{
+		{created.Add(-5 * time.Minute), true},
+		{created, true},
+		{created.Add(time.Millisecond / 2), true},
+		{created.Add(time.Millisecond), false},
+		{created.Add(5 * time.Minute), false},
+	} {
+		if got := uid64.TimeBoundCheck(id, tc.since); got != tc.want {
+			t.Errorf("TimeBoundCheck(since %v) = %t, want %t", tc.since.Sub(created), got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package uid64
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// The default generator behind the package-level NextID. It is created by
+// New on first use unless SetDefault supplies one first.
+var (
+	defaultOnce      sync.Once
+	defaultGenerator atomic.Value // *Generator
+)
+
+// Default returns the generator used by the package-level NextID, creating it
+// with New if SetDefault has not been called.
+func Default() *Generator {
+	defaultOnce.Do(func() {
+		defaultGenerator.Store(New())
+	})
+	return defaultGenerator.Load().(*Generator)
+}
+
+// SetDefault makes g the generator used by the package-level NextID. g must
+// not be nil. The generator it replaces is not closed.
+func SetDefault(g *Generator) {
+	if g == nil {
+		panic("uid64: SetDefault(nil)")
+	}
+	defaultOnce.Do(func() {})
+	defaultGenerator.Store(g)
+}
+
+// NextID returns an ID from the default generator, much as the math/rand
+// package-level functions share a default source. It is convenient for
+// programs with a single generator, but the default derives its node ID from
+// the host and takes it in the process-wide registry, and every caller in the
+// process shares its configuration and its 4096 IDs per millisecond. Services
+// that need a particular node ID, options, or isolation between components
+// should construct their own generators instead.
+func NextID() (ID, error) {
+	return Default().NextID()
+}
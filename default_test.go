@@ -0,0 +1,32 @@
+package uid64_test
+
+import (
+	"testing"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestDefault(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	gen, err := uid64.NewWithOptions(uid64.WithNodeID(9), uid64.WithClock(clock.Now), uid64.DisableRegistry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := uid64.Default()
+	defer uid64.SetDefault(prev)
+
+	uid64.SetDefault(gen)
+	if got := uid64.Default(); got != gen {
+		t.Fatalf("Default = %p, want %p", got, gen)
+	}
+	id, err := uid64.NextID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := uid64.NodeIDOf(int64(id)); got != 9 {
+		t.Errorf("NodeIDOf(NextID()) = %d, want 9", got)
+	}
+	if got := gen.LastTimestamp(); got != 1000 {
+		t.Errorf("LastTimestamp of the default = %d, want 1000", got)
+	}
+}
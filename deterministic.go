@@ -0,0 +1,38 @@
+package uid64
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// DeterministicClock returns a clock that reads startMs on its first call and
+// one millisecond more on each call after that, whatever the real time. It is
+// safe for concurrent use.
+func DeterministicClock(startMs int64) ClockFunc {
+	next := startMs - 1
+	return func() int64 {
+		return atomic.AddInt64(&next, 1)
+	}
+}
+
+// NewDeterministic returns a generator for tests that produces the same IDs
+// on every run and platform. It uses nodeID and a clock that reads seed
+// milliseconds after the default epoch until the first ID and one millisecond
+// past the last ID after that, so each NextID lands in a new millisecond with
+// sequence 0. Unlike DeterministicClock, the clock only moves when an ID is
+// issued, so methods that read it, such as Sequence, Health and
+// TimeRemaining, do not shift later IDs. It stays out of the node ID
+// registry. It panics if nodeID does not fit in the node ID bits.
+func NewDeterministic(seed int64, nodeID int) *Generator {
+	g, err := NewWithOptions(WithNodeID(nodeID), DisableRegistry())
+	if err != nil {
+		panic(fmt.Sprintf("uid64: NewDeterministic: %v", err))
+	}
+	g.clock = func() int64 {
+		if last := g.LastTimestamp(); last >= seed {
+			return last + 1
+		}
+		return seed
+	}
+	return g
+}
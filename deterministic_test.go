@@ -0,0 +1,69 @@
+package uid64_test
+
+import (
+	"testing"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestDeterministicClock(t *testing.T) {
+	clock := uid64.DeterministicClock(100)
+	for want := int64(100); want < 105; want++ {
+		if got := clock(); got != want {
+			t.Fatalf("clock() = %d, want %d", got, want)
+		}
+	}
+}
+
+func TestNewDeterministic(t *testing.T) {
+	a := uid64.NewDeterministic(1000, 7)
+	b := uid64.NewDeterministic(1000, 7)
+	for i := int64(0); i < 10; i++ {
+		x, err := a.NextInt64()
+		if err != nil {
+			t.Fatal(err)
+		}
+		y, err := b.NextInt64()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x != y {
+			t.Fatalf("ID %d differs between generators: %d != %d", i, x, y)
+		}
+		// 1000+i ms, node 7, sequence 0.
+		if want := (1000+i)<<22 | 7<<12; x != want {
+			t.Errorf("ID %d = %d, want %d", i, x, want)
+		}
+	}
+
+	// Reading the clock without issuing an ID must not move it.
+	for i := int64(10); i < 15; i++ {
+		b.Sequence()
+		b.Saturation()
+		if err := b.Health(); err != nil {
+			t.Fatal(err)
+		}
+		b.TimeRemaining()
+		x, err := a.NextInt64()
+		if err != nil {
+			t.Fatal(err)
+		}
+		y, err := b.NextInt64()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x != y {
+			t.Fatalf("ID %d differs after reading b's clock: %d != %d", i, x, y)
+		}
+		if want := (1000+i)<<22 | 7<<12; x != want {
+			t.Errorf("ID %d = %d, want %d", i, x, want)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("NewDeterministic with an out of range node ID did not panic")
+		}
+	}()
+	uid64.NewDeterministic(0, 1<<10)
+}
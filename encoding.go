@@ -0,0 +1,423 @@
+package uid64
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	// Digits needed for the largest uint64 in base 62.
+	base62Len = 11
+
+	base32Alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	// Digits needed for 64 bits in base 32.
+	base32Len = 13
+
+	hexAlphabet = "0123456789abcdef"
+	hexLen      = 16
+
+	// Bitcoin alphabet, which leaves out 0, O, I and l.
+	base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	// Digits needed for the largest uint64 in base 58.
+	base58Len = 11
+
+	// Characters of 8 bytes in unpadded base64.
+	base64URLLen = 11
+
+	// Digits of the largest uint64 in decimal.
+	postgresLen = 20
+)
+
+var (
+	ErrInvalidBase62     = errors.New("invalid base62 encoded ID")
+	ErrInvalidBase32     = errors.New("invalid base32 encoded ID")
+	ErrInvalidHex        = errors.New("invalid hex encoded ID")
+	ErrHexOverflow       = errors.New("hex encoded ID does not fit in 64 bits")
+	ErrInvalidBase58     = errors.New("invalid base58 encoded ID")
+	ErrUnknownEncoding   = errors.New("unknown encoding")
+	ErrInvalidPostgreSQL = errors.New("invalid PostgreSQL encoded ID")
+	ErrInvalidBase64URL  = errors.New("invalid base64url encoded ID")
+)
+
+// Encoding names a string representation of IDs. Its values are plain strings
+// so an encoding can be read straight from configuration. Every encoding
+// covers all 64 bits, reading IDs as unsigned, so IDs with bit 63 set, such as
+// those tagged by WithTypeTag(1), round-trip and sort after untagged ones.
+type Encoding string
+
+const (
+	EncodingBase62          Encoding = "base62"
+	EncodingBase32Crockford Encoding = "base32"
+	EncodingHex             Encoding = "hex"
+	EncodingBase58          Encoding = "base58"
+	EncodingBase64URL       Encoding = "base64url"
+)
+
+type codec struct {
+	append func([]byte, int64) []byte
+	decode func(string) (int64, error)
+}
+
+var codecs = map[Encoding]codec{
+	EncodingBase62:          {appendBase62, decodeBase62},
+	EncodingBase32Crockford: {appendBase32, decodeBase32},
+	EncodingHex:             {appendHex, decodeHex},
+	EncodingBase58:          {appendBase58, decodeBase58},
+	EncodingBase64URL:       {appendBase64URL, decodeBase64URL},
+}
+
+// longest is the width of the longest encoding, to size stack buffers.
+const longest = base32Len
+
+// ParseEncoding returns the Encoding named s, or ErrUnknownEncoding.
+func ParseEncoding(s string) (Encoding, error) {
+	enc := Encoding(s)
+	if _, ok := codecs[enc]; !ok {
+		return "", ErrUnknownEncoding
+	}
+	return enc, nil
+}
+
+// Encode encodes id using enc. It panics if enc is not one of the Encoding
+// constants; use ParseEncoding to validate encodings taken from configuration.
+func Encode(id int64, enc Encoding) string {
+	var buf [longest]byte
+	return string(AppendEncoded(buf[:0], id, enc))
+}
+
+// AppendEncoded appends Encode(id, enc) to dst and returns the extended
+// slice, allocating only if dst lacks the capacity. It panics if enc is not one of the Encoding constants.
+func AppendEncoded(dst []byte, id int64, enc Encoding) []byte {
+	c, ok := codecs[enc]
+	if !ok {
+		panic(fmt.Sprintf("uid64: unknown encoding %q", string(enc)))
+	}
+	return c.append(dst, id)
+}
+
+// Decode decodes s using enc. It returns ErrUnknownEncoding if enc is not one
+// of the Encoding constants.
+func Decode(s string, enc Encoding) (int64, error) {
+	c, ok := codecs[enc]
+	if !ok {
+		return 0, ErrUnknownEncoding
+	}
+	return c.decode(s)
+}
+
+// EncodeBase62 encodes id as an 11 character base62 string, left-padded with
+// '0'. The alphabet is in ASCII order, so for non-negative IDs the strings
+// sort the same way as the IDs.
+func EncodeBase62(id int64) string {
+	return Encode(id, EncodingBase62)
+}
+
+// DecodeBase62 decodes a string produced by EncodeBase62; the padding is
+// optional. It returns ErrInvalidBase62 if s is empty or longer than 11
+// characters, contains characters outside the alphabet or holds a value that
+// does not fit in 64 bits.
+func DecodeBase62(s string) (int64, error) {
+	return Decode(s, EncodingBase62)
+}
+
+// EncodeBase32 encodes id as a 13 character Crockford base32 string, left-padded
+// with '0'. For non-negative IDs the strings sort the same way as the IDs.
+func EncodeBase32(id int64) string {
+	return Encode(id, EncodingBase32Crockford)
+}
+
+// DecodeBase32 decodes a Crockford base32 string produced by EncodeBase32; the
+// padding is optional. Decoding is case-insensitive and, as the spec allows,
+// reads 'I' and 'L' as '1' and 'O' as '0'. It returns ErrInvalidBase32 if s is
+// empty or longer than 13 characters, contains other characters or holds a
+// value that does not fit in 64 bits.
+func DecodeBase32(s string) (int64, error) {
+	return Decode(s, EncodingBase32Crockford)
+}
+
+// EncodeHex encodes id as a 16 character lowercase hex string, left-padded with
+// '0'. For non-negative IDs the strings sort the same way as the IDs.
+func EncodeHex(id int64) string {
+	return Encode(id, EncodingHex)
+}
+
+// DecodeHex decodes a hex string produced by EncodeHex; the padding is optional
+// and either case is accepted. Malformed input yields an error wrapping
+// ErrInvalidHex, and more than 16 digits with a non-zero digit before the
+// last 16 yield ErrHexOverflow.
+func DecodeHex(s string) (int64, error) {
+	return Decode(s, EncodingHex)
+}
+
+// EncodeBase58 encodes id as an 11 character base58 string using the Bitcoin
+// alphabet, left-padded with '1', the alphabet's zero digit. For non-negative
+// IDs the strings sort the same way as the IDs.
+func EncodeBase58(id int64) string {
+	return Encode(id, EncodingBase58)
+}
+
+// DecodeBase58 decodes a string produced by EncodeBase58; the padding is
+// optional. It returns ErrInvalidBase58 if s is empty or longer than 11
+// characters, contains characters outside the alphabet or holds a value that
+// does not fit in 64 bits.
+func DecodeBase58(s string) (int64, error) {
+	return Decode(s, EncodingBase58)
+}
+
+// EncodeURLSafe encodes the 8 big-endian bytes of id, as ToBytes, in the URL
+// and filename safe base64 alphabet of RFC 4648 without padding, giving 11
+// characters that need no escaping in URL paths or queries. Unlike the other
+// encodings the alphabet is not in ASCII order, so the strings do not sort
+// like the IDs.
+func EncodeURLSafe(id int64) string {
+	return Encode(id, EncodingBase64URL)
+}
+
+// DecodeURLSafe decodes a string produced by EncodeURLSafe. It returns
+// ErrInvalidBase64URL unless s is exactly 11 characters of the alphabet with
+// the unused low bits of the last one clear.
+func DecodeURLSafe(s string) (int64, error) {
+	return Decode(s, EncodingBase64URL)
+}
+
+// SortedEncoding encodes id as a fixed-width string that sorts like the ID:
+// for non-negative IDs a < b, SortedEncoding(a) < SortedEncoding(b). It is the
+// 16 character hex form of EncodeHex, spelled out for callers that depend on
+// the property.
+func SortedEncoding(id int64) string {
+	return EncodeHex(id)
+}
+
+// SortedBase62Encoding is the 11 character counterpart of SortedEncoding,
+// using EncodeBase62.
+func SortedBase62Encoding(id int64) string {
+	return EncodeBase62(id)
+}
+
+// EncodeForPostgreSQL encodes id as a 20 digit zero-padded decimal string,
+// the width of the largest uint64, for ORMs that store IDs in NUMERIC or text
+// columns. The strings sort like the IDs compared as unsigned, as by SortIDs,
+// so IDs with the sign bit set sort last.
+func EncodeForPostgreSQL(id int64) string {
+	var buf [postgresLen]byte
+	n := uint64(id)
+	for i := postgresLen - 1; i >= 0; i-- {
+		buf[i] = byte('0' + n%10)
+		n /= 10
+	}
+	return string(buf[:])
+}
+
+// DecodeFromPostgreSQL decodes a string produced by EncodeForPostgreSQL; the
+// padding is optional. It returns ErrInvalidPostgreSQL if s is empty, longer
+// than 20 characters or contains anything but digits, including signs and
+// spaces, or holds a value that does not fit in 64 bits.
+func DecodeFromPostgreSQL(s string) (int64, error) {
+	if len(s) == 0 || len(s) > postgresLen || strings.Trim(s, "0123456789") != "" {
+		return 0, ErrInvalidPostgreSQL
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidPostgreSQL
+	}
+	return int64(n), nil
+}
+
+func appendBase62(dst []byte, id int64) []byte {
+	dst, buf := grow(dst, base62Len)
+	n := uint64(id)
+	for i := base62Len - 1; i >= 0; i-- {
+		buf[i] = base62Alphabet[n%62]
+		n /= 62
+	}
+	return dst
+}
+
+// grow extends dst by n bytes, returning the extended slice and the new bytes.
+func grow(dst []byte, n int) ([]byte, []byte) {
+	l := len(dst)
+	if cap(dst)-l < n {
+		dst = append(dst, make([]byte, n)...)
+	} else {
+		dst = dst[:l+n]
+	}
+	return dst, dst[l:]
+}
+
+func decodeBase62(s string) (int64, error) {
+	if len(s) == 0 || len(s) > base62Len {
+		return 0, ErrInvalidBase62
+	}
+	var n uint64
+	for i := 0; i < len(s); i++ {
+		d := base62Digit(s[i])
+		if d < 0 || n > (math.MaxUint64-uint64(d))/62 {
+			return 0, ErrInvalidBase62
+		}
+		n = n*62 + uint64(d)
+	}
+	return int64(n), nil
+}
+
+func base62Digit(c byte) int64 {
+	switch {
+	case c >= '0' && c <= '9':
+		return int64(c - '0')
+	case c >= 'A' && c <= 'Z':
+		return int64(c-'A') + 10
+	case c >= 'a' && c <= 'z':
+		return int64(c-'a') + 36
+	default:
+		return -1
+	}
+}
+
+func appendBase32(dst []byte, id int64) []byte {
+	dst, buf := grow(dst, base32Len)
+	n := uint64(id)
+	for i := base32Len - 1; i >= 0; i-- {
+		buf[i] = base32Alphabet[n&31]
+		n >>= 5
+	}
+	return dst
+}
+
+func decodeBase32(s string) (int64, error) {
+	if len(s) == 0 || len(s) > base32Len {
+		return 0, ErrInvalidBase32
+	}
+	var n uint64
+	for i := 0; i < len(s); i++ {
+		d := base32Digit(s[i])
+		if d < 0 || n > math.MaxUint64>>5 {
+			return 0, ErrInvalidBase32
+		}
+		n = n<<5 | uint64(d)
+	}
+	return int64(n), nil
+}
+
+func base32Digit(c byte) int64 {
+	if c >= 'a' && c <= 'z' {
+		c -= 'a' - 'A'
+	}
+	switch c {
+	case 'I', 'L':
+		return 1
+	case 'O':
+		return 0
+	}
+	return int64(strings.IndexByte(base32Alphabet, c))
+}
+
+func appendHex(dst []byte, id int64) []byte {
+	dst, buf := grow(dst, hexLen)
+	n := uint64(id)
+	for i := hexLen - 1; i >= 0; i-- {
+		buf[i] = hexAlphabet[n&15]
+		n >>= 4
+	}
+	return dst
+}
+
+func decodeHex(s string) (int64, error) {
+	if len(s) == 0 {
+		return 0, fmt.Errorf("%w: length 0, want 1 to %d", ErrInvalidHex, hexLen)
+	}
+	var (
+		n        uint64
+		overflow bool
+	)
+	for i := 0; i < len(s); i++ {
+		d := hexDigit(s[i])
+		if d < 0 {
+			return 0, fmt.Errorf("%w: bad character %q at offset %d", ErrInvalidHex, s[i], i)
+		}
+		// Digits before the last 16 are shifted out of n.
+		overflow = overflow || i < len(s)-hexLen && d != 0
+		n = n<<4 | uint64(d)
+	}
+	switch {
+	case overflow:
+		return 0, ErrHexOverflow
+	case len(s) > hexLen:
+		return 0, fmt.Errorf("%w: length %d, want 1 to %d", ErrInvalidHex, len(s), hexLen)
+	}
+	return int64(n), nil
+}
+
+// base64URL rejects encodings with unused bits set, so that every ID has a
+// single valid string.
+var base64URL = base64.RawURLEncoding.Strict()
+
+func appendBase64URL(dst []byte, id int64) []byte {
+	dst, buf := grow(dst, base64URLLen)
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(id))
+	base64URL.Encode(buf, b[:])
+	return dst
+}
+
+func decodeBase64URL(s string) (int64, error) {
+	if len(s) != base64URLLen {
+		return 0, ErrInvalidBase64URL
+	}
+	var b [8]byte
+	if _, err := base64URL.Decode(b[:], []byte(s)); err != nil {
+		return 0, ErrInvalidBase64URL
+	}
+	return int64(binary.BigEndian.Uint64(b[:])), nil
+}
+
+// MustDecodeHex is like DecodeHex but panics on error.
+func MustDecodeHex(s string) int64 {
+	id, err := DecodeHex(s)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+func hexDigit(c byte) int64 {
+	switch {
+	case c >= '0' && c <= '9':
+		return int64(c - '0')
+	case c >= 'a' && c <= 'f':
+		return int64(c-'a') + 10
+	case c >= 'A' && c <= 'F':
+		return int64(c-'A') + 10
+	default:
+		return -1
+	}
+}
+
+func appendBase58(dst []byte, id int64) []byte {
+	dst, buf := grow(dst, base58Len)
+	n := uint64(id)
+	for i := base58Len - 1; i >= 0; i-- {
+		buf[i] = base58Alphabet[n%58]
+		n /= 58
+	}
+	return dst
+}
+
+func decodeBase58(s string) (int64, error) {
+	if len(s) == 0 || len(s) > base58Len {
+		return 0, ErrInvalidBase58
+	}
+	var n uint64
+	for i := 0; i < len(s); i++ {
+		d := strings.IndexByte(base58Alphabet, s[i])
+		if d < 0 || n > (math.MaxUint64-uint64(d))/58 {
+			return 0, ErrInvalidBase58
+		}
+		n = n*58 + uint64(d)
+	}
+	return int64(n), nil
+}
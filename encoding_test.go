@@ -0,0 +1,383 @@
+package uid64_test
+
+import (
+	"errors"
+	"math"
+	"net/url"
+	"testing"
+	"testing/quick"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestBase62(t *testing.T) {
+	for _, tc := range []struct {
+		id  int64
+		enc string
+	}{
+		{0, "00000000000"},
+		{61, "0000000000z"},
+		{62, "00000000010"},
+		{math.MaxInt64, "AzL8n0Y58m7"},
+	} {
+		if got := uid64.EncodeBase62(tc.id); got != tc.enc {
+			t.Errorf("EncodeBase62(%d) = %q, want %q", tc.id, got, tc.enc)
+		}
+		got, err := uid64.DecodeBase62(tc.enc)
+		if err != nil || got != tc.id {
+			t.Errorf("DecodeBase62(%q) = %d, %v, want %d", tc.enc, got, err, tc.id)
+		}
+	}
+}
+
+func TestBase62Sorted(t *testing.T) {
+	ids, err := g.NextIDBatch(1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids = append([]int64{0, 1, 61, 62}, ids...)
+	ids = append(ids, math.MaxInt64)
+	for i := 1; i < len(ids); i++ {
+		a, b := uid64.EncodeBase62(ids[i-1]), uid64.EncodeBase62(ids[i])
+		if a >= b {
+			t.Fatalf("EncodeBase62(%d) = %q not less than EncodeBase62(%d) = %q", ids[i-1], a, ids[i], b)
+		}
+	}
+}
+
+func TestDecodeBase62Invalid(t *testing.T) {
+	for _, s := range []string{"", "0000000000-", "LygHa16AHYG", "zzzzzzzzzzz", "000000000000000000001"} {
+		if _, err := uid64.DecodeBase62(s); err != uid64.ErrInvalidBase62 {
+			t.Errorf("DecodeBase62(%q): err = %v, want ErrInvalidBase62", s, err)
+		}
+	}
+}
+
+func TestBase32(t *testing.T) {
+	for _, tc := range []struct {
+		id  int64
+		enc string
+	}{
+		{0, "0000000000000"},
+		{31, "000000000000Z"},
+		{32, "0000000000010"},
+		{math.MaxInt64, "7ZZZZZZZZZZZZ"},
+	} {
+		if got := uid64.EncodeBase32(tc.id); got != tc.enc {
+			t.Errorf("EncodeBase32(%d) = %q, want %q", tc.id, got, tc.enc)
+		}
+		got, err := uid64.DecodeBase32(tc.enc)
+		if err != nil || got != tc.id {
+			t.Errorf("DecodeBase32(%q) = %d, %v, want %d", tc.enc, got, err, tc.id)
+		}
+	}
+}
+
+func TestDecodeBase32Aliases(t *testing.T) {
+	for s, want := range map[string]int64{
+		"000000000000z": 31,
+		"00000000000i0": 32,
+		"00000000000L0": 32,
+		"0000000000oo1": 1,
+	} {
+		got, err := uid64.DecodeBase32(s)
+		if err != nil || got != want {
+			t.Errorf("DecodeBase32(%q) = %d, %v, want %d", s, got, err, want)
+		}
+	}
+}
+
+func TestDecodeBase32Invalid(t *testing.T) {
+	for _, s := range []string{"", "000000000000U", "000000000000-", "G000000000000", "00000000000000"} {
+		if _, err := uid64.DecodeBase32(s); err != uid64.ErrInvalidBase32 {
+			t.Errorf("DecodeBase32(%q): err = %v, want ErrInvalidBase32", s, err)
+		}
+	}
+}
+
+func TestBase32Quick(t *testing.T) {
+	roundTrip := func(id int64) bool {
+		if id < 0 {
+			id = -(id + 1)
+		}
+		got, err := uid64.DecodeBase32(uid64.EncodeBase32(id))
+		return err == nil && got == id
+	}
+	if err := quick.Check(roundTrip, nil); err != nil {
+		t.Error(err)
+	}
+
+	sorted := func(a, b int64) bool {
+		if a < 0 {
+			a = -(a + 1)
+		}
+		if b < 0 {
+			b = -(b + 1)
+		}
+		return (a < b) == (uid64.EncodeBase32(a) < uid64.EncodeBase32(b))
+	}
+	if err := quick.Check(sorted, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestHex(t *testing.T) {
+	for _, tc := range []struct {
+		id  int64
+		enc string
+	}{
+		{0, "0000000000000000"},
+		{255, "00000000000000ff"},
+		{math.MaxInt64, "7fffffffffffffff"},
+	} {
+		if got := uid64.EncodeHex(tc.id); got != tc.enc {
+			t.Errorf("EncodeHex(%d) = %q, want %q", tc.id, got, tc.enc)
+		}
+		if got := uid64.MustDecodeHex(tc.enc); got != tc.id {
+			t.Errorf("MustDecodeHex(%q) = %d, want %d", tc.enc, got, tc.id)
+		}
+	}
+	if got := uid64.MustDecodeHex("FF"); got != 255 {
+		t.Errorf("MustDecodeHex(%q) = %d, want 255", "FF", got)
+	}
+}
+
+func TestDecodeHexErrors(t *testing.T) {
+	for _, tc := range []struct {
+		s   string
+		err error
+	}{
+		{"", uid64.ErrInvalidHex},
+		{"00000000000000000", uid64.ErrInvalidHex},
+		{"000000000000000g", uid64.ErrInvalidHex},
+		{"10000000000000000", uid64.ErrHexOverflow},
+		{"0fffffffffffffffff", uid64.ErrHexOverflow},
+		{"1000000000000000g", uid64.ErrInvalidHex},
+	} {
+		if _, err := uid64.DecodeHex(tc.s); !errors.Is(err, tc.err) {
+			t.Errorf("DecodeHex(%q): err = %v, want %v", tc.s, err, tc.err)
+		}
+	}
+}
+
+func TestMustDecodeHexPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("MustDecodeHex did not panic")
+		}
+	}()
+	uid64.MustDecodeHex("xyz")
+}
+
+func TestBase58(t *testing.T) {
+	for _, tc := range []struct {
+		id  int64
+		enc string
+	}{
+		{0, "11111111111"},
+		{57, "1111111111z"},
+		{58, "11111111121"},
+		{1234567890123456789, "3sDK21t5nHJ"},
+		{7368411237947981824, "J72MhbbaaX9"},
+		{math.MaxInt64, "NQm6nKp8qFC"},
+	} {
+		if got := uid64.EncodeBase58(tc.id); got != tc.enc {
+			t.Errorf("EncodeBase58(%d) = %q, want %q", tc.id, got, tc.enc)
+		}
+		got, err := uid64.DecodeBase58(tc.enc)
+		if err != nil || got != tc.id {
+			t.Errorf("DecodeBase58(%q) = %d, %v, want %d", tc.enc, got, err, tc.id)
+		}
+	}
+}
+
+func TestDecodeBase58Invalid(t *testing.T) {
+	for _, s := range []string{"", "1111111111O", "1111111111l", "jpXCZedGfVR", "zzzzzzzzzzz", "111111111111"} {
+		if _, err := uid64.DecodeBase58(s); err != uid64.ErrInvalidBase58 {
+			t.Errorf("DecodeBase58(%q): err = %v, want ErrInvalidBase58", s, err)
+		}
+	}
+}
+
+func TestURLSafe(t *testing.T) {
+	for id, want := range map[int64]string{
+		0:                  "AAAAAAAAAAA",
+		0x0102030405060708: "AQIDBAUGBwg",
+		-1:                 "__________8",
+	} {
+		if got := uid64.EncodeURLSafe(id); got != want {
+			t.Errorf("EncodeURLSafe(%#x) = %q, want %q", id, got, want)
+		}
+	}
+	roundTrip := func(id int64) bool {
+		s := uid64.EncodeURLSafe(id)
+		got, err := uid64.DecodeURLSafe(s)
+		return len(s) == 11 && url.PathEscape(s) == s && url.QueryEscape(s) == s && err == nil && got == id
+	}
+	if err := quick.Check(roundTrip, nil); err != nil {
+		t.Error(err)
+	}
+
+	for _, s := range []string{"", "AAAAAAAAAA", "AAAAAAAAAAAA", "AAAAAAAAAA+", "AAAAAAAAAA/", "AAAAAAAAAA=", "AAAAAAAAAAB"} {
+		if _, err := uid64.DecodeURLSafe(s); err != uid64.ErrInvalidBase64URL {
+			t.Errorf("DecodeURLSafe(%q): err = %v, want ErrInvalidBase64URL", s, err)
+		}
+	}
+}
+
+func TestTaggedRoundTrip(t *testing.T) {
+	gen, err := uid64.NewWithOptions(uid64.WithNodeID(3), uid64.WithTypeTag(1), uid64.DisableRegistry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tagged, err := gen.NextInt64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []int64{tagged, math.MinInt64, -1} {
+		for _, enc := range []uid64.Encoding{
+			uid64.EncodingBase62,
+			uid64.EncodingBase32Crockford,
+			uid64.EncodingHex,
+			uid64.EncodingBase58,
+			uid64.EncodingBase64URL,
+		} {
+			s := uid64.Encode(id, enc)
+			if got, err := uid64.Decode(s, enc); err != nil || got != id {
+				t.Errorf("%s round trip of %d through %q = %d, %v", enc, id, s, got, err)
+			}
+			// Tagged IDs sort after every untagged one.
+			if s <= uid64.Encode(math.MaxInt64, enc) && enc != uid64.EncodingBase64URL {
+				t.Errorf("%s of %d = %q, want it after the largest untagged ID", enc, id, s)
+			}
+		}
+		if got, err := uid64.DecodeFromPostgreSQL(uid64.EncodeForPostgreSQL(id)); err != nil || got != id {
+			t.Errorf("PostgreSQL round trip of %d = %d, %v", id, got, err)
+		}
+
+		text, err := uid64.ID(id).MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got uid64.ID
+		if err := got.UnmarshalText(text); err != nil || got != uid64.ID(id) {
+			t.Errorf("text round trip of %d through %q = %d, %v", id, text, got, err)
+		}
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	id, err := g.NextInt64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"base62", "base32", "hex", "base58", "base64url"} {
+		enc, err := uid64.ParseEncoding(name)
+		if err != nil {
+			t.Fatalf("ParseEncoding(%q): %v", name, err)
+		}
+		got, err := uid64.Decode(uid64.Encode(id, enc), enc)
+		if err != nil || got != id {
+			t.Errorf("%s round trip = %d, %v, want %d", name, got, err, id)
+		}
+	}
+	if _, err := uid64.ParseEncoding("base64"); err != uid64.ErrUnknownEncoding {
+		t.Errorf("ParseEncoding: err = %v, want ErrUnknownEncoding", err)
+	}
+	if _, err := uid64.Decode("0", "base64"); err != uid64.ErrUnknownEncoding {
+		t.Errorf("Decode: err = %v, want ErrUnknownEncoding", err)
+	}
+}
+
+func TestSortedEncodings(t *testing.T) {
+	for name, enc := range map[string]struct {
+		encode func(int64) string
+		width  int
+	}{
+		"SortedEncoding":       {uid64.SortedEncoding, 16},
+		"SortedBase62Encoding": {uid64.SortedBase62Encoding, 11},
+	} {
+		sorted := func(a, b int64) bool {
+			a, b = a&math.MaxInt64, b&math.MaxInt64
+			ea, eb := enc.encode(a), enc.encode(b)
+			return len(ea) == enc.width && len(eb) == enc.width && (a < b) == (ea < eb)
+		}
+		if err := quick.Check(sorted, nil); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		// Digit carries are where a fixed-width encoding would break first.
+		for _, id := range []int64{0, 15, 61, 62 * 62, 1 << 32, math.MaxInt64 - 1} {
+			if a, b := enc.encode(id), enc.encode(id+1); a >= b {
+				t.Errorf("%s(%d) = %q, not below %s(%d) = %q", name, id, a, name, id+1, b)
+			}
+		}
+	}
+}
+
+func TestAppendEncoded(t *testing.T) {
+	for _, enc := range []uid64.Encoding{
+		uid64.EncodingBase62,
+		uid64.EncodingBase32Crockford,
+		uid64.EncodingHex,
+		uid64.EncodingBase58,
+		uid64.EncodingBase64URL,
+	} {
+		for _, id := range []int64{0, 1234567890123456789, math.MaxInt64, -1} {
+			got := uid64.AppendEncoded([]byte("id="), id, enc)
+			if want := "id=" + uid64.Encode(id, enc); string(got) != want {
+				t.Errorf("AppendEncoded(%d, %s) = %q, want %q", id, enc, got, want)
+			}
+		}
+		buf := make([]byte, 0, 16)
+		if n := testing.AllocsPerRun(100, func() { uid64.AppendEncoded(buf[:0], 42, enc) }); n != 0 {
+			t.Errorf("AppendEncoded(%s) into a large enough buffer allocates %v times", enc, n)
+		}
+	}
+}
+
+func BenchmarkEncodeBase62(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = uid64.EncodeBase62(int64(i))
+	}
+}
+
+func BenchmarkAppendEncodedBase62(b *testing.B) {
+	b.ReportAllocs()
+	buf := make([]byte, 0, 16)
+	for i := 0; i < b.N; i++ {
+		buf = uid64.AppendEncoded(buf[:0], int64(i), uid64.EncodingBase62)
+	}
+}
+
+func TestPostgreSQL(t *testing.T) {
+	for _, tc := range []struct {
+		id  int64
+		enc string
+	}{
+		{0, "00000000000000000000"},
+		{42, "00000000000000000042"},
+		{math.MaxInt64, "09223372036854775807"},
+		{-1, "18446744073709551615"},
+	} {
+		if got := uid64.EncodeForPostgreSQL(tc.id); got != tc.enc {
+			t.Errorf("EncodeForPostgreSQL(%d) = %q, want %q", tc.id, got, tc.enc)
+		}
+		if got, err := uid64.DecodeFromPostgreSQL(tc.enc); err != nil || got != tc.id {
+			t.Errorf("DecodeFromPostgreSQL(%q) = %d, %v, want %d", tc.enc, got, err, tc.id)
+		}
+	}
+	if got, err := uid64.DecodeFromPostgreSQL("42"); err != nil || got != 42 {
+		t.Errorf("DecodeFromPostgreSQL(\"42\") = %d, %v, want 42", got, err)
+	}
+	for _, s := range []string{"", " 42", "42 ", "+42", "-42", "4.2", "18446744073709551616", "000000000000000000042"} {
+		if _, err := uid64.DecodeFromPostgreSQL(s); err != uid64.ErrInvalidPostgreSQL {
+			t.Errorf("DecodeFromPostgreSQL(%q): err = %v, want ErrInvalidPostgreSQL", s, err)
+		}
+	}
+	if err := quick.Check(func(a, b int64) bool {
+		return (uint64(a) < uint64(b)) == (uid64.EncodeForPostgreSQL(a) < uid64.EncodeForPostgreSQL(b))
+	}, nil); err != nil {
+		t.Error(err)
+	}
+}
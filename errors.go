@@ -0,0 +1,44 @@
+package uid64
+
+import (
+	"fmt"
+	"time"
+)
+
+// ClockRollbackError reports that the clock read Observed after an ID had
+// been issued at Last, both in milliseconds since the generator's epoch. It
+// matches ErrInvalidState under errors.Is.
+type ClockRollbackError struct {
+	Observed int64
+	Last     int64
+}
+
+func (e *ClockRollbackError) Error() string {
+	return fmt.Sprintf("clock rolled back: observed %d < last %d (drift: %v)", e.Observed, e.Last, e.Drift())
+}
+
+func (e *ClockRollbackError) Is(target error) bool {
+	return target == ErrInvalidState
+}
+
+// Drift returns how far the clock went back, as a negative duration.
+func (e *ClockRollbackError) Drift() time.Duration {
+	return time.Duration(e.Observed-e.Last) * time.Millisecond
+}
+
+// SequenceExhaustedError reports that every sequence number of Timestamp, in
+// milliseconds since the generator's epoch, was used. It wraps Err, which is
+// the error that ended the wait for the next millisecond, such as the context
+// error of NextIDCtx, or ErrTimestampExhausted from NextIDAt.
+type SequenceExhaustedError struct {
+	Timestamp int64
+	Err       error
+}
+
+func (e *SequenceExhaustedError) Error() string {
+	return fmt.Sprintf("sequence exhausted at timestamp %d: %v", e.Timestamp, e.Err)
+}
+
+func (e *SequenceExhaustedError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,46 @@
+package uid64
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// ExpiresAt returns the instant in UTC at which g's timestamp field overflows
+// and it can no longer produce IDs. With the default epoch that is in 2084.
+func (g *Generator) ExpiresAt() time.Time {
+	return epochTime(maxTimestamp, g.epoch)
+}
+
+// TimeRemaining returns the time left until ExpiresAt, as read from g's clock.
+func (g *Generator) TimeRemaining() time.Duration {
+	return remaining(g.clock())
+}
+
+// WithExpiryWarningHook makes the generator call fn once, on the first ID
+// produced when less than threshold remains before its timestamp field
+// overflows. fn runs on the goroutine that produced the ID, so it should not
+// block.
+func WithExpiryWarningHook(threshold time.Duration, fn func(remaining time.Duration)) GeneratorOption {
+	return func(g *Generator) error {
+		g.expiryThreshold = threshold
+		g.expiryHook = fn
+		return nil
+	}
+}
+
+// checkExpiry fires the expiry warning hook if an ID stamped with timestamp
+// is the first to fall within the warning threshold.
+func (g *Generator) checkExpiry(timestamp int64) {
+	if g.expiryHook == nil || atomic.LoadUint32(&g.expiryWarned) == 1 {
+		return
+	}
+	left := remaining(timestamp)
+	if left >= g.expiryThreshold || !atomic.CompareAndSwapUint32(&g.expiryWarned, 0, 1) {
+		return
+	}
+	g.expiryHook(left)
+}
+
+func remaining(timestamp int64) time.Duration {
+	return time.Duration(maxTimestamp-timestamp) * time.Millisecond
+}
@@ -0,0 +1,81 @@
+package uid64_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+const maxTimestamp = 1<<41 - 1
+
+func TestExpiresAt(t *testing.T) {
+	want := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC).Add(maxTimestamp * time.Millisecond)
+	if got := uid64.New().ExpiresAt(); !got.Equal(want) {
+		t.Errorf("ExpiresAt = %v, want %v", got, want)
+	}
+	if got := uid64.New().ExpiresAt().Year(); got != 2084 {
+		t.Errorf("ExpiresAt year = %d, want 2084", got)
+	}
+
+	clock := &fakeClock{now: maxTimestamp - 1500}
+	gen := newTestGenerator(t, clock.Now)
+	if got := gen.TimeRemaining(); got != 1500*time.Millisecond {
+		t.Errorf("TimeRemaining = %v, want 1.5s", got)
+	}
+}
+
+func TestWithExpiryWarningHook(t *testing.T) {
+	clock := &fakeClock{now: maxTimestamp - 2*time.Hour.Milliseconds()}
+	var calls []time.Duration
+	gen, err := uid64.NewWithOptions(
+		uid64.WithNodeID(1),
+		uid64.WithClock(clock.Now),
+		uid64.WithExpiryWarningHook(time.Hour, func(remaining time.Duration) {
+			calls = append(calls, remaining)
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+	if _, err := gen.NextID(); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 0 {
+		t.Fatalf("hook fired with %v remaining, want no call above the threshold", calls)
+	}
+
+	clock.now += time.Hour.Milliseconds() + 1
+	for i := 0; i < 3; i++ {
+		if _, err := gen.NextID(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := time.Hour - time.Millisecond; len(calls) != 1 || calls[0] != want {
+		t.Errorf("hook calls = %v, want one call with %v", calls, want)
+	}
+}
+
+func TestNextIDAfterExpiry(t *testing.T) {
+	clock := &fakeClock{now: maxTimestamp}
+	gen := newTestGenerator(t, clock.Now)
+	id, err := gen.NextID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id < 0 {
+		t.Fatalf("last ID before expiry = %d, want it non-negative", id)
+	}
+
+	clock.now++
+	if _, err := gen.NextID(); err != uid64.ErrGeneratorExpired {
+		t.Errorf("NextID past ExpiresAt: err = %v, want ErrGeneratorExpired", err)
+	}
+	if _, ok := gen.TryNextID(); ok {
+		t.Error("TryNextID succeeded past ExpiresAt")
+	}
+	if _, err := gen.NextIDBatch(2); err != uid64.ErrGeneratorExpired {
+		t.Errorf("NextIDBatch past ExpiresAt: err = %v, want ErrGeneratorExpired", err)
+	}
+}
@@ -0,0 +1,40 @@
+package uid64
+
+import "time"
+
+// SetMachineIDPaths replaces the files NodeFromMachineID reads and returns a
+// function that restores them.
+func SetMachineIDPaths(paths ...string) (restore func()) {
+	saved := machineIDPaths
+	machineIDPaths = paths
+	return func() { machineIDPaths = saved }
+}
+
+// SetHostname makes the node ID strategies see name as the host name and
+// returns a function that restores the real one.
+func SetHostname(name string) (restore func()) {
+	saved := osHostname
+	osHostname = func() (string, error) { return name, nil }
+	return func() { osHostname = saved }
+}
+
+// SetLeaseLocked makes NodeFromLease call fn each time it has locked a lease
+// file, and returns a function that restores the default.
+func SetLeaseLocked(fn func(path string)) (restore func()) {
+	saved := leaseLocked
+	leaseLocked = fn
+	return func() { leaseLocked = saved }
+}
+
+// DivergenceReports feeds deltas to the detector behind WithMonotonicClock and
+// returns those it would report.
+func DivergenceReports(threshold time.Duration, deltas ...int64) []int64 {
+	d := divergenceDetector{limit: int64(threshold / time.Millisecond)}
+	var reported []int64
+	for _, delta := range deltas {
+		if d.observe(delta) {
+			reported = append(reported, delta)
+		}
+	}
+	return reported
+}
@@ -0,0 +1,49 @@
+package uid64
+
+import (
+	"errors"
+	"expvar"
+	"sync"
+)
+
+var ErrExpvarNameInUse = errors.New("expvar name is already published")
+
+// expvarLock serializes checking and publishing names, since expvar.Publish
+// panics on a duplicate.
+var expvarLock sync.Mutex
+
+// WithExpvar publishes the generator's Stats as an expvar.Map under name, so
+// they appear at /debug/vars. The map holds total_generated,
+// sequence_exhaustions, clock_rollbacks and last_timestamp_ms, each read from
+// the generator whenever the map is, so NextID pays nothing for them.
+// Construction fails with ErrExpvarNameInUse if name is already published.
+// expvar offers no way to withdraw a name, so it stays taken after Close. The
+// generators of a GeneratorPool, LocalPool or ShardedGenerator each publish
+// under name followed by a dot and their node ID, such as "ids.3", and the
+// pool fails with ErrExpvarNameInUse, publishing nothing, if any of those
+// names is taken.
+func WithExpvar(name string) GeneratorOption {
+	return func(g *Generator) error {
+		g.expvarName = name
+		return nil
+	}
+}
+
+// publishExpvar publishes g's stats if WithExpvar asked for it.
+func (g *Generator) publishExpvar() error {
+	if g.expvarName == "" {
+		return nil
+	}
+	expvarLock.Lock()
+	defer expvarLock.Unlock()
+	if expvar.Get(g.expvarName) != nil {
+		return ErrExpvarNameInUse
+	}
+	m := new(expvar.Map)
+	m.Set("total_generated", expvar.Func(func() interface{} { return g.Stats().TotalGenerated }))
+	m.Set("sequence_exhaustions", expvar.Func(func() interface{} { return g.Stats().SequenceExhaustions }))
+	m.Set("clock_rollbacks", expvar.Func(func() interface{} { return g.Stats().ClockRollbacks }))
+	m.Set("last_timestamp_ms", expvar.Func(func() interface{} { return g.LastTimestamp() }))
+	expvar.Publish(g.expvarName, m)
+	return nil
+}
@@ -0,0 +1,50 @@
+package uid64_test
+
+import (
+	"expvar"
+	"testing"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestWithExpvar(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	gen, err := uid64.NewWithOptions(uid64.WithNodeID(1), uid64.WithClock(clock.Now), uid64.WithExpvar("uid64_test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+	for i := 0; i < 1<<12; i++ {
+		if _, err := gen.NextID(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gen.TryNextID()
+	clock.now--
+	gen.TryNextID()
+
+	m, ok := expvar.Get("uid64_test").(*expvar.Map)
+	if !ok {
+		t.Fatalf("expvar.Get = %T, want *expvar.Map", expvar.Get("uid64_test"))
+	}
+	for key, want := range map[string]string{
+		"total_generated":      "4096",
+		"sequence_exhaustions": "1",
+		"clock_rollbacks":      "1",
+		"last_timestamp_ms":    "1000",
+	} {
+		if v := m.Get(key); v == nil || v.String() != want {
+			t.Errorf("%s = %v, want %s", key, v, want)
+		}
+	}
+
+	if _, err := uid64.NewWithOptions(uid64.WithNodeID(2), uid64.WithExpvar("uid64_test")); err != uid64.ErrExpvarNameInUse {
+		t.Errorf("err = %v, want ErrExpvarNameInUse", err)
+	}
+	// The failed generator must not keep its node ID.
+	other, err := uid64.NewWithNodeID(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.Close()
+}
@@ -0,0 +1,65 @@
+package uid64
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+var (
+	ErrInvalidFloodLimit = errors.New("flood protection limit must be positive")
+
+	errFloodLimited = errors.New("flood protection limit reached")
+)
+
+// WithFloodProtection caps g at maxPerSecond IDs in any one-second window, to
+// stop a runaway caller from burning through the sequence space. IDs are
+// paced rather than allowed in bursts: each is issued at least a
+// 1/maxPerSecond second after the one before, so a batch of n IDs takes at
+// least n-1 such intervals. NextID and NextIDBatch block until the limit
+// allows more IDs; NextIDCtx gives up with the context's error once it is
+// done, and TryNextID reports false instead of waiting. By default there is
+// no limit.
+func WithFloodProtection(maxPerSecond int) GeneratorOption {
+	return func(g *Generator) error {
+		if maxPerSecond <= 0 {
+			return ErrInvalidFloodLimit
+		}
+		g.floodLimit = maxPerSecond
+		g.limiter = newFloodLimiter(maxPerSecond)
+		return nil
+	}
+}
+
+// newFloodLimiter returns a limiter with a burst of one, since any larger
+// bucket would let its contents through on top of a full second's rate.
+func newFloodLimiter(maxPerSecond int) *rate.Limiter {
+	return rate.NewLimiter(rate.Limit(maxPerSecond), 1)
+}
+
+// throttle waits until the flood protection limit allows n more IDs, or
+// reports errFloodLimited at once if block is false and it does not.
+func (g *Generator) throttle(ctx context.Context, block bool, n int) error {
+	if g.limiter == nil {
+		return nil
+	}
+	if !block {
+		if !g.limiter.AllowN(time.Now(), n) {
+			return errFloodLimited
+		}
+		return nil
+	}
+	// With a burst of one, the limiter hands out one ID at a time.
+	for ; n > 0; n-- {
+		if err := g.limiter.Wait(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			// The limiter refuses up front to wait past ctx's deadline.
+			return context.DeadlineExceeded
+		}
+	}
+	return nil
+}
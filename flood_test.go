@@ -0,0 +1,78 @@
+package uid64_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestWithFloodProtection(t *testing.T) {
+	if testing.Short() {
+		t.Skip("waits on the rate limiter")
+	}
+	const limit = 50
+	gen, err := uid64.NewWithOptions(uid64.WithNodeID(1), uid64.WithFloodProtection(limit))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+
+	// No one-second window may hold more than limit IDs, that is, ID i+limit
+	// must come at least a second after ID i. Each ID is timed from before
+	// its call, and the later one from after its call, so that scheduling
+	// delays cannot make the check fail.
+	const n = limit * 3 / 2
+	var before, after [n]time.Time
+	for i := 0; i < n; i++ {
+		before[i] = time.Now()
+		if _, err := gen.NextID(); err != nil {
+			t.Fatal(err)
+		}
+		after[i] = time.Now()
+	}
+	for i := 0; i+limit < n; i++ {
+		if d := after[i+limit].Sub(before[i]); d < time.Second {
+			t.Fatalf("IDs %d to %d came within %v, want at least 1s", i, i+limit, d)
+		}
+	}
+
+	if _, ok := gen.TryNextID(); ok {
+		t.Error("TryNextID succeeded over the limit")
+	}
+	// The batch and the limit-batch IDs before it make limit+1 IDs.
+	const batch = limit / 2
+	if _, err := gen.NextIDBatch(batch); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(before[n-(limit+1-batch)]); d < time.Second {
+		t.Errorf("%d IDs including a batch came within %v, want at least 1s", limit+1, d)
+	}
+}
+
+func TestWithFloodProtectionContext(t *testing.T) {
+	gen, err := uid64.NewWithOptions(uid64.WithNodeID(1), uid64.WithFloodProtection(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+	if _, err := gen.NextID(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := gen.NextIDCtx(ctx); err != context.Canceled {
+		t.Errorf("NextIDCtx with a cancelled context: err = %v, want %v", err, context.Canceled)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := gen.NextIDCtx(ctx); err != context.DeadlineExceeded {
+		t.Errorf("NextIDCtx with a short deadline: err = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	if _, err := uid64.NewWithOptions(uid64.WithFloodProtection(0)); err != uid64.ErrInvalidFloodLimit {
+		t.Errorf("WithFloodProtection(0): err = %v, want ErrInvalidFloodLimit", err)
+	}
+}
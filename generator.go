@@ -0,0 +1,11 @@
+package uid64
+
+// IDGenerator is the interface implemented by Generator, for code that wants
+// to substitute a fake in tests; see the uid64test package.
+type IDGenerator interface {
+	NextID() (ID, error)
+	NextIDBatch(n int) ([]int64, error)
+	NodeID() int
+}
+
+var _ IDGenerator = (*Generator)(nil)
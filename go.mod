@@ -1,3 +1,14 @@
 module github.com/Ahmed-Sermani/uid64
 
 go 1.16
+
+require (
+	github.com/cespare/xxhash/v2 v2.1.2 // indirect
+	github.com/golang/protobuf v1.5.2 // indirect
+	github.com/google/go-cmp v0.5.7 // indirect
+	github.com/prometheus/client_golang v1.11.1
+	golang.org/x/sys v0.0.0-20211007075335-d3039528d8ac // indirect
+	golang.org/x/time v0.0.0-20220224211638-0e9765cccd65
+	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1 // indirect
+	google.golang.org/protobuf v1.27.1 // indirect
+)
@@ -0,0 +1,26 @@
+package uid64
+
+// Hash returns a 64 bit hash of id for hash tables and Bloom filters. Unlike
+// the ID itself, whose low bits barely change between IDs issued close
+// together, every bit of the hash depends on every bit of the ID, so IDs that
+// differ only in their sequence spread evenly. It is the SplitMix64
+// finalizer: fast, not cryptographic, and a bijection, so distinct IDs never
+// collide in the full 64 bits.
+func Hash(id int64) uint64 {
+	x := uint64(id)
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// HashMany returns Hash of each of ids, in the same order.
+func HashMany(ids []int64) []uint64 {
+	out := make([]uint64, len(ids))
+	for i, id := range ids {
+		out[i] = Hash(id)
+	}
+	return out
+}
@@ -0,0 +1,57 @@
+package uid64_test
+
+import (
+	"testing"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestHashDistribution(t *testing.T) {
+	const n = 1000000
+	base, err := uid64.NewFromComponents(1<<40, 7, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Distinct IDs never collide in 64 bits, so check the low 32 bits, as a
+	// hash table would use them; about 0.01% of n random values collide there.
+	seen := make(map[uint32]struct{}, n)
+	var buckets [64]int
+	for i := int64(0); i < n; i++ {
+		h := uid64.Hash(base + i)
+		seen[uint32(h)] = struct{}{}
+		buckets[h%64]++
+	}
+	if collisions := n - len(seen); collisions > n/1000 {
+		t.Errorf("%d of %d sequential IDs collide in the low 32 bits of Hash, want at most 0.1%%", collisions, n)
+	}
+	for i, c := range buckets {
+		if c < n/64*9/10 || c > n/64*11/10 {
+			t.Errorf("bucket %d of 64 got %d hashes, want about %d", i, c, n/64)
+		}
+	}
+}
+
+func TestHashMany(t *testing.T) {
+	ids := []int64{0, 1, 42, 1234567890123456789}
+	got := uid64.HashMany(ids)
+	if len(got) != len(ids) {
+		t.Fatalf("HashMany returned %d hashes for %d IDs", len(got), len(ids))
+	}
+	for i, id := range ids {
+		if got[i] != uid64.Hash(id) {
+			t.Errorf("HashMany[%d] = %#x, want Hash(%d) = %#x", i, got[i], id, uid64.Hash(id))
+		}
+	}
+}
+
+func BenchmarkHashMany(b *testing.B) {
+	ids := make([]int64, 1024)
+	for i := range ids {
+		ids[i] = int64(i)
+	}
+	b.SetBytes(int64(len(ids)) * 8)
+	for i := 0; i < b.N; i++ {
+		uid64.HashMany(ids)
+	}
+}
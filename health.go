@@ -0,0 +1,37 @@
+package uid64
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+var ErrGeneratorExpired = errors.New("the timestamp field has overflowed")
+
+// Health reports whether g can currently produce IDs, returning nil if it can
+// or an error describing why not: g is closed, the node ID cannot be derived,
+// the circuit breaker is open, the clock has run past ExpiresAt, or the clock
+// is further behind the last ID than NextID tolerates. It derives the node ID if that has
+// not happened yet, but is otherwise cheap enough to call from a health-check
+// handler on every request.
+func (g *Generator) Health() error {
+	if atomic.LoadUint32(&g.closed) == 1 {
+		return ErrGeneratorClosed
+	}
+	if _, err := g.resolveNodeID(context.Background()); err != nil {
+		return fmt.Errorf("deriving node ID: %w", err)
+	}
+	if g.CircuitState() == CircuitOpen {
+		return ErrCircuitOpen
+	}
+	now := g.clock()
+	if now > maxTimestamp {
+		return ErrGeneratorExpired
+	}
+	if behind := g.LastTimestamp() - now; behind > g.driftTolerance {
+		return fmt.Errorf("%w: clock is %v behind the last ID", ErrInvalidState, time.Duration(behind)*time.Millisecond)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package uid64_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestHealth(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	gen := newTestGenerator(t, clock.Now)
+	if err := gen.Health(); err != nil {
+		t.Fatalf("Health of a new generator = %v", err)
+	}
+	if _, err := gen.NextID(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gen.Health(); err != nil {
+		t.Errorf("Health = %v, want nil", err)
+	}
+
+	clock.now--
+	if err := gen.Health(); !errors.Is(err, uid64.ErrInvalidState) {
+		t.Errorf("Health with the clock behind = %v, want ErrInvalidState", err)
+	}
+	clock.now = 1 << 41
+	if err := gen.Health(); err != uid64.ErrGeneratorExpired {
+		t.Errorf("Health past expiry = %v, want ErrGeneratorExpired", err)
+	}
+
+	errStrategy := errors.New("unavailable")
+	gen, err := uid64.NewWithOptions(uid64.WithNodeIDStrategy(uid64.NodeIDStrategyFunc(func(context.Context) (int, error) {
+		return 0, errStrategy
+	})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gen.Health(); !errors.Is(err, errStrategy) {
+		t.Errorf("Health with a failing strategy = %v, want %v", err, errStrategy)
+	}
+}
+
+func TestHealthCircuitOpen(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	gen, err := uid64.NewWithOptions(uid64.WithNodeID(1), uid64.WithClock(clock.Now), uid64.WithCircuitBreaker(1, time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+	if _, err := gen.NextID(); err != nil {
+		t.Fatal(err)
+	}
+	clock.now--
+	gen.NextID()
+	clock.now++
+	if err := gen.Health(); err != uid64.ErrCircuitOpen {
+		t.Errorf("Health with the circuit open = %v, want ErrCircuitOpen", err)
+	}
+}
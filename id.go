@@ -0,0 +1,265 @@
+package uid64
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ID is an ID produced by a Generator. It can be stored in SQL columns and
+// encoded as JSON directly.
+type ID int64
+
+// String returns id in decimal, zero-padded to 19 digits, the width of the
+// largest int64, so that IDs sort the same way in text as they do as numbers.
+func (id ID) String() string {
+	return fmt.Sprintf("%019d", int64(id))
+}
+
+// GoString returns id as a Go expression, as in uid64.ID(12345678901234567).
+func (id ID) GoString() string {
+	return "uid64.ID(" + strconv.FormatInt(int64(id), 10) + ")"
+}
+
+// Format implements fmt.Formatter so that IDs print in their text form in
+// logs: %s and %v give the Base62 encoding, %q the same quoted, and %#v the
+// GoString form. The integer verbs %d, %x, %X, %o and %b format the int64
+// value as they would an int64, flags included. Use id.String() for the
+// zero-padded decimal form.
+func (id ID) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if f.Flag('#') {
+			io.WriteString(f, id.GoString())
+			return
+		}
+		fallthrough
+	case 's', 'q':
+		fmt.Fprintf(f, formatDirective(f, verb), EncodeBase62(int64(id)))
+	case 'd', 'x', 'X', 'o', 'b':
+		fmt.Fprintf(f, formatDirective(f, verb), int64(id))
+	default:
+		fmt.Fprintf(f, "%%!%c(uid64.ID=%d)", verb, int64(id))
+	}
+}
+
+// formatDirective rebuilds the directive that f was created for, with verb
+// and f's flags, width and precision, so that Format can hand its operand
+// back to fmt.
+func formatDirective(f fmt.State, verb rune) string {
+	b := []byte{'%'}
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			b = append(b, byte(flag))
+		}
+	}
+	if w, ok := f.Width(); ok {
+		b = strconv.AppendInt(b, int64(w), 10)
+	}
+	if p, ok := f.Precision(); ok {
+		b = append(b, '.')
+		b = strconv.AppendInt(b, int64(p), 10)
+	}
+	if verb == 'v' {
+		verb = 's'
+	}
+	return string(append(b, byte(verb)))
+}
+
+// Before reports whether id sorts before other, that is, whether it was
+// issued earlier when both come from generators with the same epoch.
+func (id ID) Before(other ID) bool {
+	return id < other
+}
+
+// After reports whether id sorts after other.
+func (id ID) After(other ID) bool {
+	return id > other
+}
+
+// Equal reports whether id and other are the same ID.
+func (id ID) Equal(other ID) bool {
+	return id == other
+}
+
+// TimeBefore reports whether the creation time embedded in id, read as by
+// TimeOf, is before t. IDs carry millisecond precision, so an ID issued in the
+// same millisecond as t is not before it.
+func (id ID) TimeBefore(t time.Time) bool {
+	return TimeOf(int64(id)).Before(t.Truncate(time.Millisecond))
+}
+
+// TimeAfter reports whether the creation time embedded in id, read as by
+// TimeOf, is after t.
+func (id ID) TimeAfter(t time.Time) bool {
+	return TimeOf(int64(id)).After(t)
+}
+
+// CreatedAt returns the creation time embedded in id, as TimeOf.
+func (id ID) CreatedAt() time.Time {
+	return TimeOf(int64(id))
+}
+
+// Age returns how long ago id was created, going by its embedded timestamp
+// and the default epoch. It is negative for IDs from the future.
+func (id ID) Age() time.Duration {
+	return time.Since(id.CreatedAt())
+}
+
+// MarshalText implements encoding.TextMarshaler using EncodeBase62. The rare
+// ID whose Base62 form has only digits but a different decimal value, such
+// as 62, is written in decimal instead, so that UnmarshalText reads it back.
+func (id ID) MarshalText() ([]byte, error) {
+	return []byte(id.text()), nil
+}
+
+// text returns the form MarshalText writes.
+func (id ID) text() string {
+	s := EncodeBase62(int64(id))
+	if isDecimal(s) && s != fmt.Sprintf("%011d", int64(id)) {
+		return strconv.FormatInt(int64(id), 10)
+	}
+	return s
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts the Base62
+// form produced by MarshalText and, for IDs stored before text encoding
+// existed, plain decimal. Text made only of digits, with an optional leading
+// minus sign, is read as decimal whatever its length. Anything else must be
+// the 11 characters of a Base62 ID.
+func (id *ID) UnmarshalText(text []byte) error {
+	var (
+		n   int64
+		err error
+	)
+	switch {
+	case isDecimal(string(text)):
+		n, err = strconv.ParseInt(string(text), 10, 64)
+	case len(text) == base62Len:
+		n, err = DecodeBase62(string(text))
+	default:
+		err = ErrInvalidBase62
+	}
+	if err != nil {
+		return fmt.Errorf("cannot unmarshal %q into ID: %w", text, err)
+	}
+	*id = ID(n)
+	return nil
+}
+
+// MarshalCSV implements the TypeMarshaller interface of gocsv, encoding the
+// ID as by MarshalText.
+func (id ID) MarshalCSV() (string, error) {
+	return id.text(), nil
+}
+
+// UnmarshalCSV implements the TypeUnmarshaller interface of gocsv, accepting
+// the forms UnmarshalText does.
+func (id *ID) UnmarshalCSV(s string) error {
+	return id.UnmarshalText([]byte(s))
+}
+
+// MarshalYAML implements the yaml.Marshaler interface of gopkg.in/yaml.v2 and
+// v3, encoding the ID as a string as by MarshalText.
+func (id ID) MarshalYAML() (interface{}, error) {
+	return id.text(), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface of gopkg.in/yaml.v2,
+// which v3 also honours. It accepts an integer scalar, or a string scalar in
+// any form UnmarshalText accepts.
+func (id *ID) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var n int64
+	if err := unmarshal(&n); err == nil {
+		*id = ID(n)
+		return nil
+	}
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	return id.UnmarshalText([]byte(s))
+}
+
+// Value implements driver.Valuer, storing the ID as an int64.
+func (id ID) Value() (driver.Value, error) {
+	return int64(id), nil
+}
+
+// TextValue returns the ID as its String form, for storing it in a TEXT
+// column. The zero padding keeps such a column sorted like the IDs.
+func (id ID) TextValue() (driver.Value, error) {
+	return id.String(), nil
+}
+
+// Scan implements sql.Scanner. It accepts int64 values, decimal []byte values
+// and, as from TEXT columns, string values in any form UnmarshalText accepts.
+func (id *ID) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case int64:
+		*id = ID(v)
+		return nil
+	case []byte:
+		n, err := strconv.ParseInt(string(v), 10, 64)
+		if err != nil {
+			return fmt.Errorf("cannot scan %q into ID: %w", v, err)
+		}
+		*id = ID(n)
+		return nil
+	case string:
+		return id.UnmarshalText([]byte(v))
+	default:
+		return fmt.Errorf("cannot scan %T into ID", src)
+	}
+}
+
+// UID is an ID produced by a Generator viewed as unsigned. Generated IDs never
+// set the top bit, so UID and ID hold the same values.
+type UID uint64
+
+// NextUint64 is like NextInt64 but returns the ID as a uint64, for callers
+// that store IDs in unsigned columns or want to rule out sign confusion.
+func (g *Generator) NextUint64() (uint64, error) {
+	id, err := g.NextInt64()
+	return uint64(id), err
+}
+
+// String returns the decimal form of u.
+func (u UID) String() string {
+	return strconv.FormatUint(uint64(u), 10)
+}
+
+// Value implements driver.Valuer. database/sql has no unsigned type, so the
+// value is stored as an int64.
+func (u UID) Value() (driver.Value, error) {
+	if u > math.MaxInt64 {
+		return nil, fmt.Errorf("UID %d does not fit in an int64", uint64(u))
+	}
+	return int64(u), nil
+}
+
+// Scan implements sql.Scanner. It accepts the same values as ID.Scan.
+func (u *UID) Scan(src interface{}) error {
+	var id ID
+	if err := id.Scan(src); err != nil {
+		return err
+	}
+	if id < 0 {
+		return fmt.Errorf("cannot scan negative value %d into UID", int64(id))
+	}
+	*u = UID(id)
+	return nil
+}
+
+// isDecimal reports whether s is a decimal integer: digits with an optional
+// leading minus sign.
+func isDecimal(s string) bool {
+	if strings.HasPrefix(s, "-") {
+		s = s[1:]
+	}
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
@@ -0,0 +1,51 @@
+package uid64
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// UnmarshalJSON decodes an ID from either a JSON string or a JSON number
+// holding a decimal integer, so values written in either form by
+// MarshalJSON, with or without the uid64_json_number tag, remain readable.
+func (id *ID) UnmarshalJSON(data []byte) error {
+	s, ok := jsonInteger(data)
+	if !ok {
+		return nil
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("cannot unmarshal %s into ID: %w", data, err)
+	}
+	*id = ID(n)
+	return nil
+}
+
+// UnmarshalJSON decodes a UID from a JSON string or number, like
+// ID.UnmarshalJSON.
+func (u *UID) UnmarshalJSON(data []byte) error {
+	s, ok := jsonInteger(data)
+	if !ok {
+		return nil
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("cannot unmarshal %s into UID: %w", data, err)
+	}
+	*u = UID(n)
+	return nil
+}
+
+// jsonInteger returns the digits of a JSON number or string value, unquoting
+// a string. It reports false for null, which by convention leaves the value
+// unchanged.
+func jsonInteger(data []byte) (string, bool) {
+	s := string(data)
+	if s == "null" {
+		return "", false
+	}
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+	return s, true
+}
@@ -0,0 +1,17 @@
+//go:build uid64_json_number
+// +build uid64_json_number
+
+package uid64
+
+import "strconv"
+
+// MarshalJSON encodes the ID as a JSON number, which only Go and other clients
+// with 64-bit integers decode exactly.
+func (id ID) MarshalJSON() ([]byte, error) {
+	return strconv.AppendInt(nil, int64(id), 10), nil
+}
+
+// MarshalJSON encodes u as a JSON number.
+func (u UID) MarshalJSON() ([]byte, error) {
+	return strconv.AppendUint(nil, uint64(u), 10), nil
+}
@@ -0,0 +1,21 @@
+//go:build uid64_json_number
+// +build uid64_json_number
+
+package uid64_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestIDMarshalJSONNumber(t *testing.T) {
+	data, err := json.Marshal(uid64.ID(1234567890123456789))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `1234567890123456789`; string(data) != want {
+		t.Errorf("MarshalJSON = %s, want %s", data, want)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !uid64_json_number
+// +build !uid64_json_number
+
+package uid64
+
+import "strconv"
+
+// MarshalJSON encodes the ID as a JSON string, since JavaScript numbers cannot
+// represent every int64. Build with the uid64_json_number tag to encode it as
+// a JSON number instead.
+func (id ID) MarshalJSON() ([]byte, error) {
+	return strconv.AppendQuote(nil, strconv.FormatInt(int64(id), 10)), nil
+}
+
+// MarshalJSON encodes u as a JSON string, like ID.MarshalJSON.
+func (u UID) MarshalJSON() ([]byte, error) {
+	return strconv.AppendQuote(nil, u.String()), nil
+}
@@ -0,0 +1,32 @@
+package uid64_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestIDUnmarshalJSON(t *testing.T) {
+	for _, in := range []string{`"9007199254740993"`, `9007199254740993`} {
+		var id uid64.ID
+		if err := json.Unmarshal([]byte(in), &id); err != nil || id != 9007199254740993 {
+			t.Errorf("Unmarshal(%s) = %d, %v, want 9007199254740993", in, id, err)
+		}
+		var u uid64.UID
+		if err := json.Unmarshal([]byte(in), &u); err != nil || u != 9007199254740993 {
+			t.Errorf("Unmarshal(%s) into UID = %d, %v, want 9007199254740993", in, u, err)
+		}
+	}
+
+	id := uid64.ID(7)
+	if err := json.Unmarshal([]byte(`null`), &id); err != nil || id != 7 {
+		t.Errorf("Unmarshal(null) = %d, %v, want 7 unchanged", id, err)
+	}
+	for _, in := range []string{`""`, `"12x"`, `1.5`, `true`, `"-1"`} {
+		var u uid64.UID
+		if err := json.Unmarshal([]byte(in), &u); err == nil {
+			t.Errorf("Unmarshal(%s) into UID succeeded, want error", in)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package uid64_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestIDSQL(t *testing.T) {
+	db := sql.OpenDB(&memTable{})
+	defer db.Close()
+
+	clock := &fakeClock{now: 1000}
+	gen := newTestGenerator(t, clock.Now)
+	var (
+		want  []uid64.ID
+		texts []string
+	)
+	for i := 0; i < 3; i++ {
+		id, err := gen.NextID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		clock.now += 1 << 20
+		text, err := id.TextValue()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec(`INSERT INTO ids VALUES (?, ?)`, id, text); err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, id)
+		texts = append(texts, text.(string))
+	}
+	// A row written as Base62 by some other client.
+	base62 := uid64.ID(987654321)
+	if _, err := db.Exec(`INSERT INTO ids VALUES (?, ?)`, base62, uid64.EncodeBase62(int64(base62))); err != nil {
+		t.Fatal(err)
+	}
+	want = append(want, base62)
+
+	rows, err := db.Query(`SELECT num, txt FROM ids`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var i int
+	for ; rows.Next(); i++ {
+		var num, txt uid64.ID
+		if err := rows.Scan(&num, &txt); err != nil {
+			t.Fatal(err)
+		}
+		if num != want[i] || txt != want[i] {
+			t.Errorf("row %d = %d, %d, want %d", i, num, txt, want[i])
+		}
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if i != len(want) {
+		t.Errorf("read %d rows, want %d", i, len(want))
+	}
+
+	// TEXT values sort like the IDs.
+	if !sort.StringsAreSorted(texts) {
+		t.Errorf("TextValue forms %q are not sorted", texts)
+	}
+}
+
+// memTable is a database/sql driver holding a single table in memory. Every
+// Exec appends its arguments as a row and every Query returns all rows, which
+// is enough to check how IDs cross the driver boundary without a real
+// database.
+type memTable struct {
+	mu   sync.Mutex
+	rows [][]driver.Value
+}
+
+func (m *memTable) Connect(context.Context) (driver.Conn, error) { return m, nil }
+func (m *memTable) Driver() driver.Driver                        { return nil }
+func (m *memTable) Prepare(query string) (driver.Stmt, error)    { return memStmt{m}, nil }
+func (m *memTable) Close() error                                 { return nil }
+func (m *memTable) Begin() (driver.Tx, error)                    { return nil, driver.ErrSkip }
+
+type memStmt struct{ m *memTable }
+
+func (s memStmt) Close() error  { return nil }
+func (s memStmt) NumInput() int { return -1 }
+
+func (s memStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+	s.m.rows = append(s.m.rows, args)
+	return driver.RowsAffected(1), nil
+}
+
+func (s memStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+	return &memRows{rows: append([][]driver.Value(nil), s.m.rows...)}, nil
+}
+
+type memRows struct{ rows [][]driver.Value }
+
+func (r *memRows) Columns() []string { return []string{"num", "txt"} }
+func (r *memRows) Close() error      { return nil }
+
+func (r *memRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
@@ -0,0 +1,314 @@
+package uid64_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+var (
+	_ sql.Scanner   = (*uid64.ID)(nil)
+	_ driver.Valuer = uid64.ID(0)
+)
+
+func TestIDValueScan(t *testing.T) {
+	id := uid64.ID(1234567890123456789)
+	v, err := id.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != int64(id) {
+		t.Errorf("Value = %v, want %d", v, int64(id))
+	}
+
+	for _, src := range []interface{}{
+		int64(id),
+		[]byte("1234567890123456789"),
+		"1234567890123456789",
+		uid64.EncodeBase62(int64(id)),
+	} {
+		var got uid64.ID
+		if err := got.Scan(src); err != nil {
+			t.Errorf("Scan(%v): %v", src, err)
+		}
+		if got != id {
+			t.Errorf("Scan(%v) = %d, want %d", src, got, id)
+		}
+	}
+
+	var got uid64.ID
+	for _, src := range []interface{}{nil, 1.5, "x", []byte("x")} {
+		if err := got.Scan(src); err == nil {
+			t.Errorf("Scan(%#v) succeeded, want error", src)
+		}
+	}
+}
+
+func TestIDJSONRoundTrip(t *testing.T) {
+	in := struct {
+		ID uid64.ID `json:"id"`
+	}{ID: 1234567890123456789}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := in
+	out.ID = 0
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.ID != in.ID {
+		t.Errorf("round trip through %s = %d, want %d", data, out.ID, in.ID)
+	}
+}
+
+func TestNextUint64(t *testing.T) {
+	u, err := g.NextUint64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u>>63 != 0 {
+		t.Errorf("NextUint64 = %d has the top bit set", u)
+	}
+	uid := uid64.UID(u)
+	if got, want := uid.String(), strconv.FormatUint(u, 10); got != want {
+		t.Errorf("String = %q, want %q", got, want)
+	}
+
+	v, err := uid.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var scanned uid64.UID
+	if err := scanned.Scan(v); err != nil {
+		t.Fatal(err)
+	}
+	if scanned != uid {
+		t.Errorf("Scan(Value()) = %d, want %d", scanned, uid)
+	}
+	if err := scanned.Scan(int64(-1)); err == nil {
+		t.Error("Scan(-1) succeeded, want error")
+	}
+	if _, err := uid64.UID(1 << 63).Value(); err == nil {
+		t.Error("Value of 1<<63 succeeded, want error")
+	}
+
+	data, err := json.Marshal(uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded uid64.UID
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != uid {
+		t.Errorf("JSON round trip through %s = %d, want %d", data, decoded, uid)
+	}
+}
+
+func TestIDString(t *testing.T) {
+	for _, tc := range []struct {
+		id   uid64.ID
+		want string
+	}{
+		{0, "0000000000000000000"},
+		{12345678901234567, "0012345678901234567"},
+		{math.MaxInt64, "9223372036854775807"},
+	} {
+		if got := tc.id.String(); got != tc.want {
+			t.Errorf("String(%d) = %q, want %q", int64(tc.id), got, tc.want)
+		}
+	}
+	if got, want := fmt.Sprintf("%#v", uid64.ID(12345678901234567)), "uid64.ID(12345678901234567)"; got != want {
+		t.Errorf("%%#v = %q, want %q", got, want)
+	}
+}
+
+var _ fmt.Formatter = uid64.ID(0)
+
+func TestIDFormat(t *testing.T) {
+	id := uid64.ID(1234567890123456789)
+	b62 := uid64.EncodeBase62(int64(id))
+	for _, tc := range []struct {
+		format string
+		want   string
+	}{
+		{"%s", b62},
+		{"%v", b62},
+		{"%+v", b62},
+		{"%q", `"` + b62 + `"`},
+		{"%14s|", "   " + b62 + "|"},
+		{"%-14v|", b62 + "   |"},
+		{"%#v", "uid64.ID(1234567890123456789)"},
+		{"%d", "1234567890123456789"},
+		{"%22d", "   1234567890123456789"},
+		{"%x", "112210f47de98115"},
+		{"%#X", "0X112210F47DE98115"},
+		{"%020x", "0000112210f47de98115"},
+		{"%t", "%!t(uid64.ID=1234567890123456789)"},
+	} {
+		if got := fmt.Sprintf(tc.format, id); got != tc.want {
+			t.Errorf("Sprintf(%q) = %q, want %q", tc.format, got, tc.want)
+		}
+	}
+	if got := fmt.Sprint([]uid64.ID{id, 42}); got != "["+b62+" 0000000000g]" {
+		t.Errorf("Sprint of a slice = %q", got)
+	}
+}
+
+func TestIDText(t *testing.T) {
+	id := uid64.ID(1234567890123456789)
+	text, err := id.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := uid64.EncodeBase62(int64(id)); string(text) != want {
+		t.Errorf("MarshalText = %q, want %q", text, want)
+	}
+	for _, in := range []string{string(text), "1234567890123456789"} {
+		var got uid64.ID
+		if err := got.UnmarshalText([]byte(in)); err != nil || got != id {
+			t.Errorf("UnmarshalText(%q) = %d, %v, want %d", in, got, err, id)
+		}
+	}
+	var got uid64.ID
+	if err := got.UnmarshalText([]byte("not an id")); err == nil {
+		t.Error("UnmarshalText of garbage succeeded, want error")
+	}
+
+	// Legacy decimal IDs of Base62 length must not be read as Base62.
+	for _, in := range []string{"12345678901", "-12345678901", "00012345678901"} {
+		want, _ := strconv.ParseInt(in, 10, 64)
+		var got uid64.ID
+		if err := got.UnmarshalText([]byte(in)); err != nil || int64(got) != want {
+			t.Errorf("UnmarshalText(%q) = %d, %v, want %d", in, got, err, want)
+		}
+		var scanned uid64.ID
+		if err := scanned.Scan(in); err != nil || int64(scanned) != want {
+			t.Errorf("Scan(%q) = %d, %v, want %d", in, scanned, err, want)
+		}
+		var csv uid64.ID
+		if err := csv.UnmarshalCSV(in); err != nil || int64(csv) != want {
+			t.Errorf("UnmarshalCSV(%q) = %d, %v, want %d", in, csv, err, want)
+		}
+	}
+
+	// IDs whose Base62 form has only digits still round-trip.
+	for _, id := range []uid64.ID{0, 9, 62, 1234, 839299365868340224} {
+		text, err := id.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got uid64.ID
+		if err := got.UnmarshalText(text); err != nil || got != id {
+			t.Errorf("round trip of %d through %q = %d, %v", id, text, got, err)
+		}
+	}
+}
+
+type textRecord struct {
+	ID    uid64.ID            `json:"id" xml:"id,attr"`
+	ByID  map[uid64.ID]string `json:"by_id" xml:"-"`
+	Child uid64.ID            `json:"child" xml:"child"`
+}
+
+func TestIDTextRoundTrip(t *testing.T) {
+	in := textRecord{
+		ID:    1234567890123456789,
+		ByID:  map[uid64.ID]string{42: "answer"},
+		Child: 7368411237947981824,
+	}
+	for _, codec := range []struct {
+		name      string
+		marshal   func(interface{}) ([]byte, error)
+		unmarshal func([]byte, interface{}) error
+	}{
+		{"json", json.Marshal, json.Unmarshal},
+		{"xml", xml.Marshal, xml.Unmarshal},
+	} {
+		data, err := codec.marshal(in)
+		if err != nil {
+			t.Fatalf("%s: %v", codec.name, err)
+		}
+		var out textRecord
+		if err := codec.unmarshal(data, &out); err != nil {
+			t.Fatalf("%s: %v", codec.name, err)
+		}
+		want := in
+		if codec.name == "xml" {
+			want.ByID = nil
+		}
+		if !reflect.DeepEqual(out, want) {
+			t.Errorf("%s round trip through %s = %+v, want %+v", codec.name, data, out, want)
+		}
+	}
+}
+
+func TestIDCSV(t *testing.T) {
+	for id, want := range map[uid64.ID]string{1234567890123456789: uid64.EncodeBase62(1234567890123456789), 0: "00000000000", 42: "0000000000g"} {
+		if got, err := id.MarshalCSV(); err != nil || got != want {
+			t.Errorf("MarshalCSV(%d) = %q, %v, want %q", id, got, err, want)
+		}
+	}
+
+	var id uid64.ID
+	if err := id.UnmarshalCSV("1234567890123456789"); err != nil || id != 1234567890123456789 {
+		t.Errorf("UnmarshalCSV of decimal = %d, %v", id, err)
+	}
+	if err := id.UnmarshalCSV("not an id"); err == nil {
+		t.Error("UnmarshalCSV of garbage succeeded, want error")
+	}
+}
+
+func TestIDComparison(t *testing.T) {
+	a, b := uid64.ID(100), uid64.ID(200)
+	if !a.Before(b) || a.After(b) || a.Equal(b) {
+		t.Errorf("comparisons of %d with %d are wrong", a, b)
+	}
+	if !b.After(a) || b.Before(a) || !b.Equal(200) {
+		t.Errorf("comparisons of %d with %d are wrong", b, a)
+	}
+
+	id := uid64.ID(1000 << 22)
+	at := uid64.TimeOf(int64(id))
+	for _, tc := range []struct {
+		t             time.Time
+		before, after bool
+	}{
+		{at, false, false},
+		{at.Add(time.Millisecond / 2), false, false},
+		{at.Add(time.Millisecond), true, false},
+		{at.Add(-time.Nanosecond), false, true},
+	} {
+		if got := id.TimeBefore(tc.t); got != tc.before {
+			t.Errorf("TimeBefore(%v) = %t, want %t", tc.t, got, tc.before)
+		}
+		if got := id.TimeAfter(tc.t); got != tc.after {
+			t.Errorf("TimeAfter(%v) = %t, want %t", tc.t, got, tc.after)
+		}
+	}
+}
+
+func TestIDAge(t *testing.T) {
+	ts := time.Since(uid64.DefaultEpoch()).Milliseconds() - time.Hour.Milliseconds()
+	raw, err := uid64.NewFromComponents(ts, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := uid64.ID(raw)
+	if got := id.CreatedAt(); !got.Equal(uid64.TimeOf(raw)) {
+		t.Errorf("CreatedAt = %v, want %v", got, uid64.TimeOf(raw))
+	}
+	if age := id.Age(); age < time.Hour || age > time.Hour+time.Minute {
+		t.Errorf("Age = %v, want about an hour", age)
+	}
+}
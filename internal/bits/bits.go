@@ -0,0 +1,58 @@
+// Package bits packs and unpacks the fields of uid64 IDs for a given bit
+// layout. From the top, an ID holds a spare bit 63, the timestamp, the node ID
+// and the sequence; Layout gives the widths of the last two.
+package bits
+
+// Layout holds the widths of the node ID and sequence fields. The timestamp
+// takes the bits above them up to bit 62.
+type Layout struct {
+	NodeIDBits   uint
+	SequenceBits uint
+}
+
+// LowBits returns the number of bits below the timestamp.
+func (l Layout) LowBits() uint {
+	return l.NodeIDBits + l.SequenceBits
+}
+
+// MaxTimestamp returns the largest timestamp l can hold.
+func (l Layout) MaxTimestamp() int64 {
+	return 1<<(63-l.LowBits()) - 1
+}
+
+// MaxNodeID returns the largest node ID l can hold.
+func (l Layout) MaxNodeID() int {
+	return 1<<l.NodeIDBits - 1
+}
+
+// MaxSequence returns the largest sequence l can hold.
+func (l Layout) MaxSequence() int64 {
+	return 1<<l.SequenceBits - 1
+}
+
+// Pack composes an ID from its fields. They are not checked against the
+// field widths; a value too wide spills into the fields above it.
+func Pack(timestamp, nodeID, sequence int64, l Layout) int64 {
+	return timestamp<<l.LowBits() | nodeID<<l.SequenceBits | sequence
+}
+
+// UnpackTimestamp returns the timestamp of id, ignoring bit 63.
+func UnpackTimestamp(id int64, l Layout) int64 {
+	return id >> l.LowBits() & l.MaxTimestamp()
+}
+
+// UnpackNodeID returns the node ID of id.
+func UnpackNodeID(id int64, l Layout) int {
+	return int(id>>l.SequenceBits) & l.MaxNodeID()
+}
+
+// UnpackSequence returns the sequence of id.
+func UnpackSequence(id int64, l Layout) int64 {
+	return id & l.MaxSequence()
+}
+
+// Low returns the node ID and sequence of id, in place, with the timestamp and
+// bit 63 cleared.
+func Low(id int64, l Layout) int64 {
+	return id & (1<<l.LowBits() - 1)
+}
@@ -0,0 +1,36 @@
+package bits_test
+
+import (
+	"math"
+	"testing"
+	"testing/quick"
+
+	"github.com/Ahmed-Sermani/uid64/internal/bits"
+)
+
+func TestPackUnpack(t *testing.T) {
+	for _, l := range []bits.Layout{{10, 12}, {5, 17}, {16, 6}} {
+		roundTrip := func(ts, nodeID, seq int64) bool {
+			ts &= l.MaxTimestamp()
+			nodeID &= int64(l.MaxNodeID())
+			seq &= l.MaxSequence()
+			id := bits.Pack(ts, nodeID, seq, l)
+			return id >= 0 &&
+				bits.UnpackTimestamp(id, l) == ts &&
+				bits.UnpackNodeID(id, l) == int(nodeID) &&
+				bits.UnpackSequence(id, l) == seq &&
+				bits.Low(id, l) == nodeID<<l.SequenceBits|seq
+		}
+		if err := quick.Check(roundTrip, nil); err != nil {
+			t.Errorf("layout %+v: %v", l, err)
+		}
+	}
+
+	l := bits.Layout{NodeIDBits: 10, SequenceBits: 12}
+	if got := l.MaxTimestamp(); got != 1<<41-1 {
+		t.Errorf("MaxTimestamp = %d, want 2^41-1", got)
+	}
+	if id := int64(math.MinInt64) | bits.Pack(7, 3, 5, l); bits.UnpackTimestamp(id, l) != 7 || bits.Low(id, l) != 3<<12|5 {
+		t.Errorf("bit 63 leaked into the fields of %#x", uint64(id))
+	}
+}
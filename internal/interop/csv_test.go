@@ -0,0 +1,33 @@
+package interop_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gocarina/gocsv"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+type csvRecord struct {
+	ID   uid64.ID `csv:"id"`
+	Name string   `csv:"name"`
+}
+
+func TestIDCSV(t *testing.T) {
+	in := []*csvRecord{{1234567890123456789, "a"}, {0, "b"}, {42, "c"}}
+	data, err := gocsv.MarshalBytes(&in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "id,name\n" + uid64.EncodeBase62(1234567890123456789) + ",a\n00000000000,b\n0000000000g,c\n"; string(data) != want {
+		t.Errorf("MarshalBytes = %q, want %q", data, want)
+	}
+	var out []*csvRecord
+	if err := gocsv.UnmarshalBytes(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("round trip through %q = %v, want %v", data, out, in)
+	}
+}
@@ -0,0 +1,3 @@
+// Package interop tests uid64 IDs against third-party encoders. It is a
+// module of its own so that their packages are not requirements of uid64.
+package interop
@@ -0,0 +1,11 @@
+module github.com/Ahmed-Sermani/uid64/internal/interop
+
+go 1.16
+
+require (
+	github.com/Ahmed-Sermani/uid64 v0.0.0
+	github.com/gocarina/gocsv v0.0.0-20220310154401-d4df709ca055
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+replace github.com/Ahmed-Sermani/uid64 => ../../
@@ -0,0 +1,83 @@
+package interop_test
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+var _ yaml.Marshaler = uid64.ID(0)
+
+func TestIDYAML(t *testing.T) {
+	for _, id := range []uid64.ID{0, 42, 1234567890123456789, 12345678901} {
+		data, err := yaml.Marshal(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var node yaml.Node
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			t.Fatal(err)
+		}
+		if n := node.Content[0]; n.Kind != yaml.ScalarNode || n.Tag != "!!str" || n.Value != uid64.EncodeBase62(int64(id)) {
+			t.Errorf("YAML of %d = %s, want a Base62 string scalar", id, data)
+		}
+		var got uid64.ID
+		if err := yaml.Unmarshal(data, &got); err != nil || got != id {
+			t.Errorf("round trip of %d through %s = %d, %v", id, data, got, err)
+		}
+	}
+
+	for in, want := range map[string]uid64.ID{
+		"12345678901":           12345678901,
+		"1234567890123456789":   1234567890123456789,
+		`"1234567890123456789"`: 1234567890123456789,
+		`"0000000000g"`:         42,
+	} {
+		var got uid64.ID
+		if err := yaml.Unmarshal([]byte(in), &got); err != nil || got != want {
+			t.Errorf("Unmarshal(%s) = %d, %v, want %d", in, got, err, want)
+		}
+	}
+	var got uid64.ID
+	if err := yaml.Unmarshal([]byte("[1]"), &got); err == nil {
+		t.Error("Unmarshal of a sequence succeeded, want error")
+	}
+
+	// Legacy decimal IDs of Base62 length must not be read as Base62.
+	for _, in := range []string{"12345678901", "-12345678901", "00012345678901"} {
+		want, _ := strconv.ParseInt(in, 10, 64)
+		var got uid64.ID
+		if err := yaml.Unmarshal([]byte(strconv.Quote(in)), &got); err != nil || int64(got) != want {
+			t.Errorf("YAML %q = %d, %v, want %d", in, got, err, want)
+		}
+	}
+}
+
+type yamlRecord struct {
+	ID    uid64.ID            `yaml:"id"`
+	ByID  map[uid64.ID]string `yaml:"by_id"`
+	Child uid64.ID            `yaml:"child"`
+}
+
+func TestIDYAMLRoundTrip(t *testing.T) {
+	in := yamlRecord{
+		ID:    1234567890123456789,
+		ByID:  map[uid64.ID]string{42: "answer"},
+		Child: 7368411237947981824,
+	}
+	data, err := yaml.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out yamlRecord
+	if err := yaml.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("round trip through %s = %+v, want %+v", data, out, in)
+	}
+}
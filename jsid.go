@@ -0,0 +1,43 @@
+package uid64
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// JSID is an ID that always travels in JSON as an 11 character Base62 string,
+// which is compact and safe from the precision loss of JavaScript numbers
+// whatever the uid64_json_number tag says. Convert with JSID(id) and int64(j).
+type JSID int64
+
+// MarshalJSON encodes j as a quoted Base62 string.
+func (j JSID) MarshalJSON() ([]byte, error) {
+	var buf [base62Len + 2]byte
+	b := append(buf[:0], '"')
+	b = AppendEncoded(b, int64(j), EncodingBase62)
+	return append(b, '"'), nil
+}
+
+// UnmarshalJSON decodes a JSID from a Base62 JSON string, as produced by
+// MarshalJSON, or from a JSON number holding a decimal integer. null leaves j
+// unchanged.
+func (j *JSID) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	var (
+		n   int64
+		err error
+	)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		n, err = DecodeBase62(s[1 : len(s)-1])
+	} else {
+		n, err = strconv.ParseInt(s, 10, 64)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot unmarshal %s into JSID: %w", data, err)
+	}
+	*j = JSID(n)
+	return nil
+}
@@ -0,0 +1,37 @@
+package uid64_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestJSID(t *testing.T) {
+	const id = int64(1234567890123456789)
+	data, err := json.Marshal(struct {
+		ID uid64.JSID `json:"id"`
+	}{uid64.JSID(id)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"id":"` + uid64.EncodeBase62(id) + `"}`; string(data) != want {
+		t.Errorf("Marshal = %s, want %s", data, want)
+	}
+
+	for _, in := range []string{`"` + uid64.EncodeBase62(id) + `"`, "1234567890123456789"} {
+		var got uid64.JSID
+		if err := json.Unmarshal([]byte(in), &got); err != nil || int64(got) != id {
+			t.Errorf("Unmarshal(%s) = %d, %v, want %d", in, got, err, id)
+		}
+	}
+	got := uid64.JSID(7)
+	if err := json.Unmarshal([]byte("null"), &got); err != nil || got != 7 {
+		t.Errorf("Unmarshal(null) = %d, %v, want 7 unchanged", got, err)
+	}
+	for _, in := range []string{`"not base62!"`, "1.5", `"0000000000000"`} {
+		if err := json.Unmarshal([]byte(in), &got); err == nil {
+			t.Errorf("Unmarshal(%s) succeeded, want error", in)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package uid64
+
+import (
+	"errors"
+
+	"github.com/Ahmed-Sermani/uid64/internal/bits"
+)
+
+var (
+	ErrInvalidBitLayout        = errors.New("node ID and sequence bits must add up to 22")
+	ErrInvalidDatacenterLayout = errors.New("datacenter and worker bits must add up to the node ID bits")
+)
+
+// layout describes how the node ID and sequence share the low 22 bits of an ID.
+// The field widths and the packing are in internal/bits; maxNodeID and
+// maxSequence cache the limits they imply.
+type layout struct {
+	bits.Layout
+	maxNodeID   int
+	maxSequence int64
+
+	// datacenterBits and workerBits split the node ID into a datacenter ID in
+	// its high bits and a worker ID in its low bits. Both are zero unless
+	// WithDatacenterLayout is used.
+	datacenterBits uint
+	workerBits     uint
+
+	// tag is OR-ed into every composed ID: the sign bit if the generator was
+	// configured with WithTypeTag(1), otherwise zero.
+	tag int64
+}
+
+var defaultLayout = newLayout(nodeIDBits, sequenceBits)
+
+// MaxNodeID returns the largest node ID of the default bit layout, 1023.
+func MaxNodeID() int {
+	return defaultLayout.maxNodeID
+}
+
+// MaxSequence returns the largest sequence number of the default bit layout,
+// 4095.
+func MaxSequence() int {
+	return int(defaultLayout.maxSequence)
+}
+
+// MaxNodeID returns the largest node ID g's bit layout can hold.
+func (g *Generator) MaxNodeID() int {
+	return g.layout.maxNodeID
+}
+
+// MaxSequence returns the largest sequence number g's bit layout can hold.
+func (g *Generator) MaxSequence() int {
+	return int(g.layout.maxSequence)
+}
+
+func newLayout(nodeIDBits, sequenceBits uint) layout {
+	bl := bits.Layout{NodeIDBits: nodeIDBits, SequenceBits: sequenceBits}
+	return layout{
+		Layout:      bl,
+		maxNodeID:   bl.MaxNodeID(),
+		maxSequence: bl.MaxSequence(),
+	}
+}
+
+func (l layout) compose(timestamp int64, nodeID int, sequence int64) int64 {
+	return bits.Pack(timestamp, int64(nodeID), sequence, l.Layout) | l.tag
+}
+
+// timestamp masks off the sign bit, which may hold a type tag.
+func (l layout) timestamp(id int64) int64 {
+	return bits.UnpackTimestamp(id, l.Layout)
+}
+
+func (l layout) nodeID(id int64) int {
+	return bits.UnpackNodeID(id, l.Layout)
+}
+
+func (l layout) sequence(id int64) int64 {
+	return bits.UnpackSequence(id, l.Layout)
+}
+
+func (l layout) hasDatacenter() bool {
+	return l.datacenterBits+l.workerBits > 0
+}
+
+func (l layout) maxDatacenterID() int {
+	return 1<<l.datacenterBits - 1
+}
+
+func (l layout) maxWorkerID() int {
+	return 1<<l.workerBits - 1
+}
+
+func (l layout) splitNodeID(nodeID int) (datacenterID, workerID int) {
+	return nodeID >> l.workerBits, nodeID & l.maxWorkerID()
+}
+
+// packState stores the timestamp offset by one so that the zero state word
+// stands for a generator that has not produced an ID yet.
+func (l layout) packState(lastTimestamp, sequence int64) uint64 {
+	return uint64(lastTimestamp+1)<<l.SequenceBits | uint64(sequence)
+}
+
+func (l layout) unpackState(state uint64) (lastTimestamp, sequence int64) {
+	return int64(state>>l.SequenceBits) - 1, int64(state) & l.maxSequence
+}
@@ -0,0 +1,143 @@
+package uid64
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrLeaseHeld        = errors.New("node ID lease is held by another process")
+	ErrLeaseNotHeld     = errors.New("node ID lease is not held by this process")
+	ErrLeaseUnsupported = errors.New("node ID leases are not supported on this platform")
+)
+
+// leases holds the lease files this process has locked, keyed by cleaned path.
+// Each stays open, and so locked, until ReleaseNodeLease.
+var leases = struct {
+	sync.Mutex
+	files map[string]*lease
+}{files: make(map[string]*lease)}
+
+type lease struct {
+	f      *os.File
+	nodeID int
+}
+
+// NodeFromLease returns the node ID recorded in the lease file at path,
+// creating the file with a node ID derived as by the default strategy if it
+// does not exist, so a node gets the same ID back after a restart. The file is
+// locked with flock(2) until ReleaseNodeLease or the process exits, and
+// NodeFromLease returns ErrLeaseHeld if another process holds the lock. Calling
+// it again for a lease the process already holds returns the same node ID.
+func NodeFromLease(path string) (int, error) {
+	path = filepath.Clean(path)
+	leases.Lock()
+	defer leases.Unlock()
+	if l, ok := leases.files[path]; ok {
+		return l.nodeID, nil
+	}
+
+	f, err := openLease(path)
+	if err != nil {
+		return 0, err
+	}
+	nodeID, err := readOrCreateLease(f)
+	if err != nil {
+		unlockFile(f)
+		f.Close()
+		return 0, err
+	}
+	leases.files[path] = &lease{f: f, nodeID: nodeID}
+	return nodeID, nil
+}
+
+// leaseLocked is called by openLease once it has locked a file, so that tests
+// can replace the file at that point.
+var leaseLocked = func(path string) {}
+
+// openLease opens and locks the lease file at path. A process releasing the
+// lease unlinks the file before unlocking it, so a lock won on a file opened
+// before the unlink belongs to a file nobody else will look at; openLease
+// checks that the locked file is still the one at path and retries if not.
+func openLease(path string) (*os.File, error) {
+	for {
+		f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		if err := lockFile(f); err != nil {
+			f.Close()
+			return nil, err
+		}
+		leaseLocked(path)
+		locked, err := f.Stat()
+		if err != nil {
+			unlockFile(f)
+			f.Close()
+			return nil, err
+		}
+		current, err := os.Stat(path)
+		if err == nil && os.SameFile(locked, current) {
+			return f, nil
+		}
+		unlockFile(f)
+		f.Close()
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+}
+
+// ReleaseNodeLease removes the lease file at path and drops its lock, so the
+// next NodeFromLease derives a node ID afresh. It returns ErrLeaseNotHeld
+// unless the process acquired the lease with NodeFromLease.
+func ReleaseNodeLease(path string) error {
+	path = filepath.Clean(path)
+	leases.Lock()
+	defer leases.Unlock()
+	l, ok := leases.files[path]
+	if !ok {
+		return ErrLeaseNotHeld
+	}
+	delete(leases.files, path)
+	// Remove the file before unlocking, so no other process can lock it and
+	// read the node ID in between.
+	err := os.Remove(path)
+	unlockFile(l.f)
+	if cerr := l.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func readOrCreateLease(f *os.File) (int, error) {
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return 0, err
+	}
+	if s := strings.TrimSpace(string(data)); s != "" {
+		nodeID, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, fmt.Errorf("parsing lease %s: %w", f.Name(), err)
+		}
+		if nodeID < 0 || nodeID > maxNodeID {
+			return 0, ErrOutOfBoundNodeID
+		}
+		return nodeID, nil
+	}
+
+	nodeID, err := createNodeID(maxNodeID)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := f.WriteString(strconv.Itoa(nodeID) + "\n"); err != nil {
+		return 0, err
+	}
+	return nodeID, f.Sync()
+}
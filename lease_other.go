@@ -0,0 +1,12 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package uid64
+
+import "os"
+
+func lockFile(f *os.File) error {
+	return ErrLeaseUnsupported
+}
+
+func unlockFile(f *os.File) {}
@@ -0,0 +1,114 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package uid64_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestNodeFromLease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node.lease")
+	nodeID, err := uid64.NodeFromLease(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(data)); got != strconv.Itoa(nodeID) {
+		t.Errorf("lease file holds %q, want %d", got, nodeID)
+	}
+	if again, err := uid64.NodeFromLease(path); err != nil || again != nodeID {
+		t.Errorf("second NodeFromLease = %d, %v, want %d", again, err, nodeID)
+	}
+
+	if err := uid64.ReleaseNodeLease(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("lease file after release: err = %v, want ErrNotExist", err)
+	}
+	if err := uid64.ReleaseNodeLease(path); err != uid64.ErrLeaseNotHeld {
+		t.Errorf("second release: err = %v, want ErrLeaseNotHeld", err)
+	}
+}
+
+func TestNodeFromLeaseExisting(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "node.lease")
+	if err := os.WriteFile(path, []byte("17\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := uid64.NodeFromLease(path); err != nil || got != 17 {
+		t.Errorf("NodeFromLease = %d, %v, want 17", got, err)
+	}
+	defer uid64.ReleaseNodeLease(path)
+
+	for content, want := range map[string]error{"1024": uid64.ErrOutOfBoundNodeID, "node": nil} {
+		bad := filepath.Join(dir, content)
+		if err := os.WriteFile(bad, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := uid64.NodeFromLease(bad)
+		if err == nil || want != nil && err != want {
+			t.Errorf("lease holding %q: err = %v, want %v", content, err, want)
+		}
+	}
+}
+
+func TestNodeFromLeaseHeld(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node.lease")
+	// A lock on a separate open file stands in for another process.
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uid64.NodeFromLease(path); err != uid64.ErrLeaseHeld {
+		t.Errorf("err = %v, want ErrLeaseHeld", err)
+	}
+}
+
+func TestNodeFromLeaseReplaced(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node.lease")
+	if err := os.WriteFile(path, []byte("5\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Another process releases the lease and a third creates it afresh after
+	// NodeFromLease opened the old file but before it checks its lock.
+	replaced := false
+	defer uid64.SetLeaseLocked(func(path string) {
+		if replaced {
+			return
+		}
+		replaced = true
+		if err := os.Remove(path); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("77\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	})()
+
+	nodeID, err := uid64.NodeFromLease(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer uid64.ReleaseNodeLease(path)
+	if nodeID != 77 {
+		t.Errorf("NodeFromLease = %d, want 77 from the file now at the path", nodeID)
+	}
+}
@@ -0,0 +1,84 @@
+package uid64
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/Ahmed-Sermani/uid64/internal/bits"
+)
+
+var (
+	ErrTimestampExhausted  = errors.New("all sequence numbers for the timestamp are used")
+	ErrTimestampUnderflow  = errors.New("ID was created before the target epoch")
+	ErrTimestampOverflow   = errors.New("ID was created too long after the target epoch")
+	ErrTimestampIssued     = errors.New("timestamp is not before the first ID issued by NextID")
+	ErrTimestampOutOfOrder = errors.New("timestamp is before the previous NextIDAt timestamp")
+)
+
+// NextIDAt returns an ID stamped with t rather than the current time, for
+// migrating historical records while keeping their creation order. t must lie
+// at or after the epoch and strictly before the millisecond of the first ID g
+// issued through NextID, or before the current millisecond if it has issued
+// none, so that imported IDs can never collide with generated ones. It fails
+// with ErrTimestampIssued otherwise. Records must be imported in
+// chronological order: only the sequence of the latest millisecond is kept,
+// so a t earlier than the previous call's fails with ErrTimestampOutOfOrder.
+//
+// IDs issued under the same node ID by other processes, such as the one whose
+// state NewFromState resumed, are not known to g; their milliseconds are not
+// excluded.
+func (g *Generator) NextIDAt(t time.Time) (int64, error) {
+	ts := unixMilli(t) - g.epoch
+	switch {
+	case atomic.LoadUint32(&g.closed) == 1:
+		return 0, ErrGeneratorClosed
+	case ts < 0:
+		return 0, ErrTimestampTooOld
+	case ts >= g.clock():
+		return 0, ErrTimestampInFuture
+	case ts > g.layout.MaxTimestamp():
+		return 0, ErrGeneratorExpired
+	}
+	if first := atomic.LoadInt64(&g.firstTimestamp); first != 0 && ts >= first-1 {
+		return 0, ErrTimestampIssued
+	}
+	nodeID, err := g.resolveNodeID(context.Background())
+	if err != nil {
+		return 0, err
+	}
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	switch last := g.importTimestamp - 1; {
+	case ts < last:
+		return 0, ErrTimestampOutOfOrder
+	case ts > last:
+		g.importTimestamp, g.importSequence = ts+1, 0
+	}
+	seq := g.importSequence
+	if seq > g.layout.maxSequence {
+		return 0, &SequenceExhaustedError{Timestamp: ts, Err: ErrTimestampExhausted}
+	}
+	g.importSequence++
+	return g.layout.compose(ts, nodeID, seq), nil
+}
+
+// MigrateEpoch rewrites id, produced by a generator with epoch fromEpoch, into
+// the ID a generator with epoch toEpoch would have produced at the same
+// millisecond. The node ID, sequence and bit 63 are kept as they are, which
+// assumes the default bit layout. It fails with ErrTimestampUnderflow if id
+// was created before toEpoch and with ErrTimestampOverflow if its timestamp
+// relative to toEpoch does not fit in 41 bits.
+func MigrateEpoch(id int64, fromEpoch, toEpoch time.Time) (int64, error) {
+	ts := defaultLayout.timestamp(id) + unixMilli(fromEpoch) - unixMilli(toEpoch)
+	switch {
+	case ts < 0:
+		return 0, ErrTimestampUnderflow
+	case ts > maxTimestamp:
+		return 0, ErrTimestampOverflow
+	}
+	return bits.Pack(ts, 0, 0, defaultLayout.Layout) | id&math.MinInt64 | bits.Low(id, defaultLayout.Layout), nil
+}
@@ -0,0 +1,147 @@
+package uid64_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestNextIDAt(t *testing.T) {
+	gen, err := uid64.NewWithNodeID(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+
+	created := time.Date(2019, 6, 1, 12, 30, 0, 0, time.UTC)
+	var prev int64
+	for i := 0; i < 3; i++ {
+		id, err := gen.NextIDAt(created)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := uid64.Decompose(id)
+		if !c.Time.Equal(created) || c.NodeID != 8 || c.Sequence != int64(i) {
+			t.Errorf("Decompose = %+v, want time %v node 8 sequence %d", c, created, i)
+		}
+		if id <= prev {
+			t.Errorf("NextIDAt = %d, want greater than %d", id, prev)
+		}
+		prev = id
+	}
+
+	id, err := gen.NextIDAt(created.Add(time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := uid64.SequenceOf(id); got != 0 {
+		t.Errorf("SequenceOf for another millisecond = %d, want 0", got)
+	}
+	if _, err := gen.NextIDAt(created); err != uid64.ErrTimestampOutOfOrder {
+		t.Errorf("NextIDAt for an earlier millisecond: err = %v, want ErrTimestampOutOfOrder", err)
+	}
+}
+
+func TestNextIDAtErrors(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	gen := newTestGenerator(t, clock.Now)
+	for _, tc := range []struct {
+		t   time.Time
+		err error
+	}{
+		{uid64.TimeOf(0).Add(-time.Millisecond), uid64.ErrTimestampTooOld},
+		{uid64.TimeOf(0).Add(1001 * time.Millisecond), uid64.ErrTimestampInFuture},
+		{uid64.TimeOf(0).Add(1000 * time.Millisecond), uid64.ErrTimestampInFuture},
+	} {
+		if _, err := gen.NextIDAt(tc.t); err != tc.err {
+			t.Errorf("NextIDAt(%v): err = %v, want %v", tc.t, err, tc.err)
+		}
+	}
+
+	at := uid64.TimeOf(0).Add(500 * time.Millisecond)
+	for i := 0; i < 1<<12; i++ {
+		if _, err := gen.NextIDAt(at); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := gen.NextIDAt(at); !errors.Is(err, uid64.ErrTimestampExhausted) {
+		t.Errorf("err = %v, want ErrTimestampExhausted", err)
+	}
+}
+
+func TestNextIDAtAfterNextID(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	gen := newTestGenerator(t, clock.Now)
+	if _, err := gen.NextID(); err != nil {
+		t.Fatal(err)
+	}
+	clock.now = 1010
+	for _, ms := range []time.Duration{1000, 1005} {
+		at := uid64.TimeOf(0).Add(ms * time.Millisecond)
+		if _, err := gen.NextIDAt(at); err != uid64.ErrTimestampIssued {
+			t.Errorf("NextIDAt(%v): err = %v, want ErrTimestampIssued", at, err)
+		}
+	}
+	at := uid64.TimeOf(0).Add(999 * time.Millisecond)
+	id, err := gen.NextIDAt(at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := uid64.TimeOf(id); !got.Equal(at) {
+		t.Errorf("TimeOf = %v, want %v", got, at)
+	}
+}
+
+func TestMigrateEpoch(t *testing.T) {
+	created := time.Date(2021, 3, 4, 5, 6, 7, 8e6, time.UTC)
+	from := uid64.DefaultEpoch()
+	to := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	id, err := uid64.NewFromComponents(created.Sub(from).Milliseconds(), 513, 77)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	migrated, err := uid64.MigrateEpoch(id, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := uid64.DecomposeWithEpoch(migrated, to)
+	if !c.Time.Equal(created) || c.NodeID != 513 || c.Sequence != 77 {
+		t.Errorf("DecomposeWithEpoch = %+v, want time %v node 513 sequence 77", c, created)
+	}
+	if back, err := uid64.MigrateEpoch(migrated, to, from); err != nil || back != id {
+		t.Errorf("MigrateEpoch back = %d, %v, want %d", back, err, id)
+	}
+	tagged := uid64.EmbedVersion(id, 1)
+	if got, err := uid64.MigrateEpoch(tagged, from, to); err != nil || got != uid64.EmbedVersion(migrated, 1) {
+		t.Errorf("MigrateEpoch of a version 1 ID = %d, %v, want bit 63 kept", got, err)
+	}
+
+	if _, err := uid64.MigrateEpoch(id, from, created.Add(time.Millisecond)); err != uid64.ErrTimestampUnderflow {
+		t.Errorf("MigrateEpoch to a later epoch: err = %v, want ErrTimestampUnderflow", err)
+	}
+	if _, err := uid64.MigrateEpoch(id, from, from.AddDate(-70, 0, 0)); err != uid64.ErrTimestampOverflow {
+		t.Errorf("MigrateEpoch to an epoch 70 years earlier: err = %v, want ErrTimestampOverflow", err)
+	}
+}
+
+func TestNextIDAtAfterReset(t *testing.T) {
+	clock := &fakeClock{now: 100000}
+	gen := newTestGenerator(t, clock.Now)
+	if _, err := gen.NextID(); err != nil {
+		t.Fatal(err)
+	}
+	gen.Reset()
+	clock.now = 5000
+	id, err := gen.NextInt64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock.now = 6000
+	imported, err := gen.NextIDAt(uid64.TimeOf(id))
+	if err != uid64.ErrTimestampIssued {
+		t.Errorf("NextIDAt at the millisecond NextID used after Reset = %d, %v, want ErrTimestampIssued", imported, err)
+	}
+}
@@ -0,0 +1,97 @@
+package uid64
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+var ErrInvalidDivergenceThreshold = errors.New("clock divergence threshold must be positive")
+
+// defaultDivergenceThreshold is how far the wall clock may drift from the
+// monotonic clock before WithMonotonicClock reports it.
+const defaultDivergenceThreshold = time.Second
+
+// MonotonicClockFunc returns a clock counting from the default epoch that
+// follows the monotonic clock rather than the wall clock from the moment it
+// is created, so it never goes backwards when the wall clock is stepped, for
+// example by NTP or a misbehaving daylight saving time transition. It does
+// follow the slewing NTP applies to correct gradual drift. Use
+// WithMonotonicClock instead to also have divergence reported.
+func MonotonicClockFunc() ClockFunc {
+	return monotonicClock(customEpoch, 0, nil)
+}
+
+// WithMonotonicClock makes the generator use a clock like MonotonicClockFunc,
+// counting from the generator's epoch and anchored when the generator is
+// constructed. Whenever the wall clock ends up more than the divergence
+// threshold, one second unless set by WithClockDivergenceThreshold, away from
+// the monotonic reading, the StatsHook's OnClockDivergence is called. It is
+// ignored if WithClock is also given.
+func WithMonotonicClock() GeneratorOption {
+	return func(g *Generator) error {
+		g.monotonic = true
+		return nil
+	}
+}
+
+// WithClockDivergenceThreshold sets how far the wall clock may move from the
+// monotonic clock set by WithMonotonicClock before OnClockDivergence is
+// called.
+func WithClockDivergenceThreshold(threshold time.Duration) GeneratorOption {
+	return func(g *Generator) error {
+		if threshold <= 0 {
+			return ErrInvalidDivergenceThreshold
+		}
+		g.divergenceThreshold = threshold
+		return nil
+	}
+}
+
+// monotonicClock returns a clock counting from epoch that advances with the
+// monotonic clock. If onDivergence is not nil, it is called with the wall
+// clock's lead in milliseconds, which is negative if the wall clock is behind,
+// each time the lead moves more than threshold from the one last reported.
+func monotonicClock(epoch int64, threshold time.Duration, onDivergence func(delta int64)) ClockFunc {
+	start := time.Now()
+	startMs := unixMilli(start) - epoch
+	d := divergenceDetector{limit: int64(threshold / time.Millisecond)}
+	return func() int64 {
+		now := time.Now()
+		ms := startMs + int64(now.Sub(start)/time.Millisecond)
+		if onDivergence != nil {
+			if delta := unixMilli(now) - epoch - ms; d.observe(delta) {
+				onDivergence(delta)
+			}
+		}
+		return ms
+	}
+}
+
+// divergenceDetector decides which wall clock leads are worth reporting: those
+// beyond limit that differ by more than limit from the last one reported, so
+// that a single clock step is reported once rather than on every reading. The
+// clocks coming back within limit of each other is not reported but forgets
+// the last report, so that the next step is.
+type divergenceDetector struct {
+	limit    int64
+	reported int64
+}
+
+func (d *divergenceDetector) observe(delta int64) bool {
+	last := atomic.LoadInt64(&d.reported)
+	if abs(delta) <= d.limit {
+		if last != 0 {
+			atomic.CompareAndSwapInt64(&d.reported, last, 0)
+		}
+		return false
+	}
+	return abs(delta-last) > d.limit && atomic.CompareAndSwapInt64(&d.reported, last, delta)
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
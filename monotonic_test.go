@@ -0,0 +1,55 @@
+package uid64_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestMonotonicClockFunc(t *testing.T) {
+	clock := uid64.MonotonicClockFunc()
+	last := clock()
+	if got := uid64.TimeOf(last << 22); time.Since(got) > time.Second || time.Since(got) < -time.Second {
+		t.Errorf("clock reads %v, want about now", got)
+	}
+	for i := 0; i < 1000; i++ {
+		ms := clock()
+		if ms < last {
+			t.Fatalf("clock went from %d back to %d", last, ms)
+		}
+		last = ms
+	}
+}
+
+func TestWithMonotonicClock(t *testing.T) {
+	epoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	gen, err := uid64.NewWithOptions(uid64.WithNodeID(1), uid64.WithEpoch(epoch), uid64.WithMonotonicClock())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+	before := time.Now().Truncate(time.Millisecond)
+	id, err := gen.NextInt64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	after := time.Now()
+	if c := uid64.DecomposeWithEpoch(id, epoch); c.Time.Before(before) || c.Time.After(after) {
+		t.Errorf("Time = %v, want between %v and %v", c.Time, before, after)
+	}
+
+	if _, err := uid64.NewWithOptions(uid64.WithClockDivergenceThreshold(0)); err != uid64.ErrInvalidDivergenceThreshold {
+		t.Errorf("threshold 0: err = %v, want ErrInvalidDivergenceThreshold", err)
+	}
+}
+
+func TestClockDivergenceReports(t *testing.T) {
+	// Each step of the wall clock is reported once, including a repeat of a
+	// step that was undone in between.
+	got := uid64.DivergenceReports(time.Second, 0, 3, 3600000, 3600001, 7200000, 1, 3600000, 0, -5000, -5001)
+	if want := []int64{3600000, 7200000, 3600000, -5000}; !reflect.DeepEqual(got, want) {
+		t.Errorf("reports = %v, want %v", got, want)
+	}
+}
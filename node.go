@@ -0,0 +1,156 @@
+package uid64
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrNodeIDEnvUnset = errors.New("node ID environment variable is not set")
+	ErrNoMachineID    = errors.New("no machine-id file found")
+	ErrNotIPv4        = errors.New("IP address is not IPv4")
+	ErrNoPodOrdinal   = errors.New("host name does not end in a StatefulSet pod ordinal")
+	ErrNoPodUID       = errors.New("pod UID is not set")
+)
+
+// machineIDPaths lists where NodeFromMachineID looks, in order.
+var machineIDPaths = []string{"/etc/machine-id", "/var/lib/dbus/machine-id"}
+
+// osHostname is os.Hostname, replaceable in tests.
+var osHostname = os.Hostname
+
+// podUIDEnv is the environment variable NodeFromKubernetesPodUID falls back
+// to, conventionally filled from metadata.uid by the downward API.
+const podUIDEnv = "MY_POD_UID"
+
+// ChainedStrategy tries each strategy in order and returns the first node ID
+// one of them produces, or the error of the last one if all fail. Unlike
+// ChainedNodeIDStrategy it falls back on any error.
+type ChainedStrategy []NodeIDStrategy
+
+func (c ChainedStrategy) NodeID(ctx context.Context) (int, error) {
+	err := errors.New("no node ID strategies")
+	for _, s := range c {
+		var nodeID int
+		if nodeID, err = s.NodeID(ctx); err == nil {
+			return nodeID, nil
+		}
+	}
+	return 0, err
+}
+
+// NodeFromHostname derives a node ID from a hash of the host name. Unlike the
+// MAC address default it never falls back to a random value, so the node ID is
+// the same across restarts.
+func NodeFromHostname() (int, error) {
+	hostname, err := osHostname()
+	if err != nil {
+		return 0, err
+	}
+	return hashNodeID([]byte(hostname), maxNodeID), nil
+}
+
+// NodeFromKubernetesStatefulSet uses the ordinal of a StatefulSet pod, the
+// number after the last '-' of its host name, as the node ID. Each pod of the
+// set then has a distinct node ID that survives rescheduling, without any
+// coordination. It returns ErrNoPodOrdinal if the host name has no such
+// suffix and ErrOutOfBoundNodeID if the set has more than 1024 replicas.
+func NodeFromKubernetesStatefulSet() (int, error) {
+	hostname, err := osHostname()
+	if err != nil {
+		return 0, err
+	}
+	suffix := hostname[strings.LastIndexByte(hostname, '-')+1:]
+	if suffix == "" || suffix == hostname || strings.Trim(suffix, "0123456789") != "" {
+		return 0, ErrNoPodOrdinal
+	}
+	ordinal, err := strconv.Atoi(suffix)
+	if err != nil {
+		// Too many digits for an int.
+		return 0, ErrOutOfBoundNodeID
+	}
+	if ordinal > maxNodeID {
+		return 0, ErrOutOfBoundNodeID
+	}
+	return ordinal, nil
+}
+
+// NodeFromKubernetesPodUID derives a node ID from a hash of a pod UID. If uid
+// is empty it is read from the MY_POD_UID environment variable, which the
+// downward API can set from metadata.uid; ErrNoPodUID is returned if that is
+// empty too. Unlike NodeFromKubernetesStatefulSet this works for any pod, but
+// distinct pods may hash to the same node ID.
+func NodeFromKubernetesPodUID(uid string) (int, error) {
+	if uid == "" {
+		uid = os.Getenv(podUIDEnv)
+	}
+	if uid == "" {
+		return 0, ErrNoPodUID
+	}
+	return hashNodeID([]byte(uid), maxNodeID), nil
+}
+
+// NodeFromEnv reads a decimal node ID from the environment variable envVar.
+// It returns ErrNodeIDEnvUnset if the variable is empty or unset, and
+// ErrOutOfBoundNodeID if the value does not fit in the node ID bits.
+func NodeFromEnv(envVar string) (int, error) {
+	v := os.Getenv(envVar)
+	if v == "" {
+		return 0, ErrNodeIDEnvUnset
+	}
+	nodeID, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("parsing %s: %w", envVar, err)
+	}
+	if nodeID < 0 || nodeID > maxNodeID {
+		return 0, ErrOutOfBoundNodeID
+	}
+	return nodeID, nil
+}
+
+// hashNodeID hashes b with FNV-32a and masks the result to [0, mask].
+func hashNodeID(b []byte, mask int) int {
+	h := fnv.New32a()
+	h.Write(b)
+	return int(h.Sum32()) & mask
+}
+
+// NodeFromMachineID derives a node ID from a hash of the systemd machine ID,
+// read from /etc/machine-id or, failing that, /var/lib/dbus/machine-id. This
+// suits containers and VMs without a usable network interface. It returns
+// ErrNoMachineID if neither file exists.
+func NodeFromMachineID() (int, error) {
+	for _, path := range machineIDPaths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		id, err := hex.DecodeString(strings.ReplaceAll(strings.TrimSpace(string(data)), "-", ""))
+		if err != nil || len(id) == 0 {
+			return 0, fmt.Errorf("invalid machine ID in %s", path)
+		}
+		return hashNodeID(id, maxNodeID), nil
+	}
+	return 0, ErrNoMachineID
+}
+
+// NodeFromIPv4 derives a node ID from a hash of ip, giving operators explicit
+// control over which address identifies the node, for example a Kubernetes pod
+// IP. It returns ErrNotIPv4 unless ip is an IPv4 address.
+func NodeFromIPv4(ip net.IP) (int, error) {
+	ip4 := ip.To4()
+	if ip4 == nil {
+		return 0, ErrNotIPv4
+	}
+	return hashNodeID(ip4, maxNodeID), nil
+}
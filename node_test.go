@@ -0,0 +1,176 @@
+package uid64_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestNodeFromHostname(t *testing.T) {
+	first, err := uid64.NodeFromHostname()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first < 0 || first > 1023 {
+		t.Errorf("NodeFromHostname = %d, want within [0, 1023]", first)
+	}
+	second, err := uid64.NodeFromHostname()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Errorf("NodeFromHostname not stable: %d then %d", first, second)
+	}
+}
+
+func TestNodeFromEnv(t *testing.T) {
+	const envVar = "UID64_TEST_NODE_ID"
+	defer os.Unsetenv(envVar)
+
+	os.Setenv(envVar, "42")
+	if got, err := uid64.NodeFromEnv(envVar); err != nil || got != 42 {
+		t.Errorf("NodeFromEnv = %d, %v, want 42", got, err)
+	}
+
+	for value, want := range map[string]error{
+		"":     uid64.ErrNodeIDEnvUnset,
+		"1024": uid64.ErrOutOfBoundNodeID,
+		"-1":   uid64.ErrOutOfBoundNodeID,
+	} {
+		os.Setenv(envVar, value)
+		if _, err := uid64.NodeFromEnv(envVar); !errors.Is(err, want) {
+			t.Errorf("NodeFromEnv with %q: err = %v, want %v", value, err, want)
+		}
+	}
+
+	os.Setenv(envVar, "node-1")
+	if _, err := uid64.NodeFromEnv(envVar); err == nil {
+		t.Error("NodeFromEnv with a non-numeric value succeeded, want error")
+	}
+}
+
+func TestNodeFromMachineID(t *testing.T) {
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "missing")
+	machineID := filepath.Join(dir, "machine-id")
+	if err := os.WriteFile(machineID, []byte("4c4c4544003510588036b4c04f4e3732\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	defer uid64.SetMachineIDPaths(missing, machineID)()
+	nodeID, err := uid64.NodeFromMachineID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nodeID < 0 || nodeID > 1023 {
+		t.Errorf("NodeFromMachineID = %d, want within [0, 1023]", nodeID)
+	}
+	if again, _ := uid64.NodeFromMachineID(); again != nodeID {
+		t.Errorf("NodeFromMachineID not stable: %d then %d", nodeID, again)
+	}
+
+	uid64.SetMachineIDPaths(missing)
+	if _, err := uid64.NodeFromMachineID(); err != uid64.ErrNoMachineID {
+		t.Errorf("err = %v, want ErrNoMachineID", err)
+	}
+
+	if err := os.WriteFile(machineID, []byte("not hex"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	uid64.SetMachineIDPaths(machineID)
+	if _, err := uid64.NodeFromMachineID(); err == nil {
+		t.Error("NodeFromMachineID with an invalid file succeeded, want error")
+	}
+}
+
+func TestChainedStrategy(t *testing.T) {
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+	fail := func(err error) uid64.NodeIDStrategy {
+		return uid64.NodeIDStrategyFunc(func(context.Context) (int, error) { return 0, err })
+	}
+	fixed := uid64.NodeIDStrategyFunc(func(context.Context) (int, error) { return 7, nil })
+
+	if got, err := (uid64.ChainedStrategy{fail(errFirst), fixed, fail(errSecond)}).NodeID(context.Background()); err != nil || got != 7 {
+		t.Errorf("NodeID = %d, %v, want 7", got, err)
+	}
+	if _, err := (uid64.ChainedStrategy{fail(errFirst), fail(errSecond)}).NodeID(context.Background()); err != errSecond {
+		t.Errorf("err = %v, want %v", err, errSecond)
+	}
+	if _, err := (uid64.ChainedStrategy{}).NodeID(context.Background()); err == nil {
+		t.Error("empty ChainedStrategy succeeded, want error")
+	}
+}
+
+func TestNodeFromIPv4(t *testing.T) {
+	a, err := uid64.NodeFromIPv4(net.ParseIP("10.0.0.1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a < 0 || a > 1023 {
+		t.Errorf("NodeFromIPv4 = %d, want within [0, 1023]", a)
+	}
+	// The IPv4-in-IPv6 form of the same address hashes the same 4 bytes.
+	if b, err := uid64.NodeFromIPv4(net.ParseIP("::ffff:10.0.0.1")); err != nil || b != a {
+		t.Errorf("NodeFromIPv4(::ffff:10.0.0.1) = %d, %v, want %d", b, err, a)
+	}
+	if b, _ := uid64.NodeFromIPv4(net.ParseIP("10.0.0.2")); b == a {
+		t.Errorf("10.0.0.1 and 10.0.0.2 both map to %d", a)
+	}
+
+	for _, ip := range []net.IP{net.ParseIP("2001:db8::1"), nil} {
+		if _, err := uid64.NodeFromIPv4(ip); err != uid64.ErrNotIPv4 {
+			t.Errorf("NodeFromIPv4(%v): err = %v, want ErrNotIPv4", ip, err)
+		}
+	}
+}
+
+func TestNodeFromKubernetesStatefulSet(t *testing.T) {
+	for hostname, want := range map[string]struct {
+		nodeID int
+		err    error
+	}{
+		"web-0":          {0, nil},
+		"kafka-broker-7": {7, nil},
+		"web-1023":       {1023, nil},
+		"web-1024":       {0, uid64.ErrOutOfBoundNodeID},
+		"web":            {0, uid64.ErrNoPodOrdinal},
+		"web-":           {0, uid64.ErrNoPodOrdinal},
+		"web-abc":        {0, uid64.ErrNoPodOrdinal},
+		"web-+1":         {0, uid64.ErrNoPodOrdinal},
+		"web--1":         {1, nil},
+	} {
+		restore := uid64.SetHostname(hostname)
+		got, err := uid64.NodeFromKubernetesStatefulSet()
+		restore()
+		if got != want.nodeID || err != want.err {
+			t.Errorf("host %q: NodeFromKubernetesStatefulSet = %d, %v, want %d, %v", hostname, got, err, want.nodeID, want.err)
+		}
+	}
+}
+
+func TestNodeFromKubernetesPodUID(t *testing.T) {
+	const uid = "9b4e3a43-2f3c-4c5f-8f0e-3d2b1a0c9e8d"
+	nodeID, err := uid64.NodeFromKubernetesPodUID(uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nodeID < 0 || nodeID > 1023 {
+		t.Errorf("NodeFromKubernetesPodUID = %d, want within [0, 1023]", nodeID)
+	}
+
+	defer os.Unsetenv("MY_POD_UID")
+	os.Setenv("MY_POD_UID", uid)
+	if got, err := uid64.NodeFromKubernetesPodUID(""); err != nil || got != nodeID {
+		t.Errorf("NodeFromKubernetesPodUID from env = %d, %v, want %d", got, err, nodeID)
+	}
+	os.Unsetenv("MY_POD_UID")
+	if _, err := uid64.NodeFromKubernetesPodUID(""); err != uid64.ErrNoPodUID {
+		t.Errorf("err = %v, want ErrNoPodUID", err)
+	}
+}
@@ -0,0 +1,138 @@
+package uid64
+
+import (
+	"context"
+	"time"
+)
+
+// GeneratorOption configures a Generator built by NewWithOptions.
+type GeneratorOption func(*Generator) error
+
+// ClockFunc returns the current time in milliseconds since the generator's epoch.
+type ClockFunc func() int64
+
+// NodeIDStrategy derives the node ID of a generator that was not given one
+// explicitly. NodeID is called on first use with the context of the NextIDCtx
+// call that needs the node ID, or a background context.
+type NodeIDStrategy interface {
+	NodeID(ctx context.Context) (int, error)
+}
+
+// NodeIDStrategyFunc adapts an ordinary function to a NodeIDStrategy.
+type NodeIDStrategyFunc func(ctx context.Context) (int, error)
+
+func (f NodeIDStrategyFunc) NodeID(ctx context.Context) (int, error) {
+	return f(ctx)
+}
+
+// WithNodeID sets the node ID instead of deriving it from the host. It is
+// checked against the bit layout once all options have been applied.
+func WithNodeID(nodeID int) GeneratorOption {
+	return func(g *Generator) error {
+		g.nodeID = nodeID
+		g.nodeIDResolved = 1
+		return nil
+	}
+}
+
+// WithEpoch sets the instant timestamps are counted from, instead of the
+// default of 2015-01-01 UTC. The epoch must lie between 2000-01-01 UTC and now,
+// and must leave at least ten years in the timestamp field. Decompose IDs from
+// such a generator with DecomposeWithEpoch.
+func WithEpoch(epoch time.Time) GeneratorOption {
+	return func(g *Generator) error {
+		if epoch.Before(minEpoch) {
+			return ErrEpochTooEarly
+		}
+		ms := unixMilli(epoch)
+		now := unixMilli(time.Now())
+		switch {
+		case ms > now:
+			return ErrEpochInFuture
+		case maxTimestamp-(now-ms) < minEpochRange:
+			return ErrEpochRangeInsufficient
+		}
+		g.epoch = ms
+		return nil
+	}
+}
+
+// WithClock replaces the system clock used to timestamp IDs. NextID polls the
+// clock while waiting for the next millisecond, so a clock that never advances
+// blocks it once the sequence is exhausted.
+func WithClock(clock ClockFunc) GeneratorOption {
+	return func(g *Generator) error {
+		g.clock = clock
+		return nil
+	}
+}
+
+// WithNodeIDStrategy sets how the node ID is derived when none is given.
+// The default hashes the MAC addresses of the host's network interfaces.
+func WithNodeIDStrategy(strategy NodeIDStrategy) GeneratorOption {
+	return func(g *Generator) error {
+		g.strategy = strategy
+		return nil
+	}
+}
+
+// WithMaxBatchSize sets the largest n accepted by NextIDBatch. The default is 4096.
+func WithMaxBatchSize(n int) GeneratorOption {
+	return func(g *Generator) error {
+		if n < 1 {
+			return ErrInvalidBatchSize
+		}
+		g.maxBatchSize = n
+		return nil
+	}
+}
+
+// WithBitLayout splits the 22 bits below the timestamp into nodeBits of node ID
+// and seqBits of sequence, instead of the default 10 and 12. Decompose IDs from
+// such a generator with Generator.Decompose.
+func WithBitLayout(nodeBits, seqBits uint) GeneratorOption {
+	return func(g *Generator) error {
+		if unusedBits+epochBits+nodeBits+seqBits != 64 {
+			return ErrInvalidBitLayout
+		}
+		g.layout = newLayout(nodeBits, seqBits)
+		return nil
+	}
+}
+
+// WithDatacenterLayout splits the node ID into a datacenter ID in its high
+// datacenterBits and a worker ID in its low workerBits, which must add up to
+// the node ID bits of the layout. Generator.Decompose then reports both.
+func WithDatacenterLayout(datacenterBits, workerBits uint) GeneratorOption {
+	return func(g *Generator) error {
+		g.datacenterBits = datacenterBits
+		g.workerBits = workerBits
+		return nil
+	}
+}
+
+// WithClockDriftTolerance makes NextID wait for the clock to catch up when it
+// goes backwards by at most d, as a small NTP correction would, instead of
+// failing with ErrInvalidState. Larger rollbacks fail with an error wrapping
+// ErrExcessiveClockDrift that reports the drift. The default is zero.
+func WithClockDriftTolerance(d time.Duration) GeneratorOption {
+	return func(g *Generator) error {
+		if d < 0 {
+			return ErrInvalidDriftTolerance
+		}
+		g.driftTolerance = int64(d / time.Millisecond)
+		return nil
+	}
+}
+
+// WithInitialSequence makes the first ID carry sequence seq rather than 0, for
+// a process that restarts within the millisecond of its last ID and knows the
+// sequence that ID used. To carry over the timestamp as well, persist the state
+// from MarshalBinary and resume with NewFromState instead. seq is checked
+// against the bit layout once all options have been applied.
+func WithInitialSequence(seq int64) GeneratorOption {
+	return func(g *Generator) error {
+		g.initialSequence = seq
+		return nil
+	}
+}
@@ -0,0 +1,225 @@
+package uid64_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestNewWithOptions(t *testing.T) {
+	gen, err := uid64.NewWithOptions(
+		uid64.WithNodeID(7),
+		uid64.WithClock(func() int64 { return 1000 }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+	id, err := gen.NextInt64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := uid64.Decompose(id)
+	if c.NodeID != 7 || c.Timestamp != 1000 || c.Sequence != 0 {
+		t.Errorf("Decompose = %+v, want node 7, timestamp 1000, sequence 0", c)
+	}
+}
+
+func TestWithNodeIDOutOfBound(t *testing.T) {
+	for _, nodeID := range []int{-1, 1 << 10} {
+		if _, err := uid64.NewWithOptions(uid64.WithNodeID(nodeID)); !errors.Is(err, uid64.ErrOutOfBoundNodeID) {
+			t.Errorf("WithNodeID(%d): err = %v, want ErrOutOfBoundNodeID", nodeID, err)
+		}
+	}
+}
+
+func TestWithNodeIDStrategy(t *testing.T) {
+	gen, err := uid64.NewWithOptions(uid64.WithNodeIDStrategy(uid64.NodeIDStrategyFunc(func(context.Context) (int, error) {
+		return 99, nil
+	})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+	id, err := gen.NextInt64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := uid64.NodeIDOf(id); got != 99 {
+		t.Errorf("NodeIDOf = %d, want 99", got)
+	}
+
+	errStrategy := errors.New("no node ID")
+	gen, err = uid64.NewWithOptions(uid64.WithNodeIDStrategy(uid64.NodeIDStrategyFunc(func(context.Context) (int, error) {
+		return 0, errStrategy
+	})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gen.NextID(); !errors.Is(err, errStrategy) {
+		t.Errorf("NextID err = %v, want %v", err, errStrategy)
+	}
+}
+
+func TestWithEpoch(t *testing.T) {
+	epoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	gen, err := uid64.NewWithOptions(uid64.WithNodeID(3), uid64.WithEpoch(epoch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+	before := time.Now().Truncate(time.Millisecond)
+	id, err := gen.NextInt64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	after := time.Now()
+
+	c := uid64.DecomposeWithEpoch(id, epoch)
+	if c.Time.Before(before) || c.Time.After(after) {
+		t.Errorf("Time = %v, want between %v and %v", c.Time, before, after)
+	}
+	if want := c.Time.Sub(epoch).Milliseconds(); c.Timestamp != want {
+		t.Errorf("Timestamp = %d, want %d", c.Timestamp, want)
+	}
+	if c.NodeID != 3 {
+		t.Errorf("NodeID = %d, want 3", c.NodeID)
+	}
+}
+
+func TestWithEpochErrors(t *testing.T) {
+	for _, tc := range []struct {
+		epoch time.Time
+		err   error
+	}{
+		{time.Now().Add(time.Hour), uid64.ErrEpochInFuture},
+		{time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC), uid64.ErrEpochTooEarly},
+		{time.Time{}, uid64.ErrEpochTooEarly},
+		{time.Date(1999, 12, 31, 23, 59, 59, 0, time.UTC), uid64.ErrEpochTooEarly},
+	} {
+		if _, err := uid64.NewWithOptions(uid64.WithEpoch(tc.epoch)); err != tc.err {
+			t.Errorf("WithEpoch(%v): err = %v, want %v", tc.epoch, err, tc.err)
+		}
+	}
+}
+
+func TestWithBitLayout(t *testing.T) {
+	clock := func() int64 { return 1000 }
+	gen, err := uid64.NewWithOptions(uid64.WithBitLayout(5, 17), uid64.WithNodeID(31), uid64.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+	var last int64
+	for i := 0; i < 1<<16; i++ {
+		if last, err = gen.NextInt64(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := gen.Decompose(last)
+	if c.Timestamp != 1000 || c.NodeID != 31 || c.Sequence != 1<<16-1 {
+		t.Errorf("Decompose = %+v, want timestamp 1000 node 31 sequence %d", c, 1<<16-1)
+	}
+	if gen.MaxNodeID() != 31 || gen.MaxSequence() != 1<<17-1 {
+		t.Errorf("MaxNodeID, MaxSequence = %d, %d, want 31, %d", gen.MaxNodeID(), gen.MaxSequence(), 1<<17-1)
+	}
+	if uid64.MaxNodeID() != 1023 || uid64.MaxSequence() != 4095 {
+		t.Errorf("default MaxNodeID, MaxSequence = %d, %d, want 1023, 4095", uid64.MaxNodeID(), uid64.MaxSequence())
+	}
+
+	if _, err := uid64.NewWithOptions(uid64.WithBitLayout(5, 17), uid64.WithNodeID(32)); err != uid64.ErrOutOfBoundNodeID {
+		t.Errorf("node 32 with 5 node bits: err = %v, want ErrOutOfBoundNodeID", err)
+	}
+	if _, err := uid64.NewWithOptions(uid64.WithBitLayout(10, 10)); err != uid64.ErrInvalidBitLayout {
+		t.Errorf("WithBitLayout(10, 10): err = %v, want ErrInvalidBitLayout", err)
+	}
+}
+
+func TestNewWithDatacenterWorker(t *testing.T) {
+	gen, err := uid64.NewWithDatacenterWorker(3, 17)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+	id, err := gen.NextInt64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := gen.Decompose(id)
+	if c.DatacenterID != 3 || c.WorkerID != 17 || c.NodeID != 3<<5|17 {
+		t.Errorf("Decompose = %+v, want datacenter 3 worker 17 node %d", c, 3<<5|17)
+	}
+
+	gen, err = uid64.NewWithDatacenterWorker(1, 255, uid64.WithDatacenterLayout(2, 8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+	if id, err = gen.NextInt64(); err != nil {
+		t.Fatal(err)
+	}
+	if c := gen.Decompose(id); c.DatacenterID != 1 || c.WorkerID != 255 {
+		t.Errorf("Decompose = %+v, want datacenter 1 worker 255", c)
+	}
+}
+
+func TestNewWithDatacenterWorkerErrors(t *testing.T) {
+	for _, tc := range []struct {
+		datacenterID, workerID int
+		opts                   []uid64.GeneratorOption
+		err                    error
+	}{
+		{32, 0, nil, uid64.ErrOutOfBoundDatacenterID},
+		{0, 32, nil, uid64.ErrOutOfBoundWorkerID},
+		{-1, 0, nil, uid64.ErrOutOfBoundDatacenterID},
+		{0, 0, []uid64.GeneratorOption{uid64.WithDatacenterLayout(4, 4)}, uid64.ErrInvalidDatacenterLayout},
+		{0, 0, []uid64.GeneratorOption{uid64.WithBitLayout(12, 10)}, uid64.ErrInvalidDatacenterLayout},
+	} {
+		if _, err := uid64.NewWithDatacenterWorker(tc.datacenterID, tc.workerID, tc.opts...); err != tc.err {
+			t.Errorf("NewWithDatacenterWorker(%d, %d): err = %v, want %v", tc.datacenterID, tc.workerID, err, tc.err)
+		}
+	}
+}
+
+func TestWithInitialSequence(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	gen, err := uid64.NewWithOptions(uid64.WithNodeID(1), uid64.WithClock(clock.Now), uid64.WithInitialSequence(501))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+	for _, want := range []int64{501, 502} {
+		id, err := gen.NextInt64()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := uid64.SequenceOf(id); got != want {
+			t.Errorf("SequenceOf = %d, want %d", got, want)
+		}
+	}
+	clock.now++
+	ids, err := gen.NextIDBatch(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := uid64.SequenceOf(ids[0]); got != 0 {
+		t.Errorf("SequenceOf in the next millisecond = %d, want 0", got)
+	}
+
+	gen.Reset()
+	if ids, err = gen.NextIDBatch(2); err != nil {
+		t.Fatal(err)
+	}
+	if got := uid64.SequenceOf(ids[0]); got != 501 {
+		t.Errorf("SequenceOf after Reset = %d, want 501", got)
+	}
+
+	for _, seq := range []int64{-1, 1 << 12} {
+		if _, err := uid64.NewWithOptions(uid64.WithInitialSequence(seq)); err != uid64.ErrInvalidInitialSequence {
+			t.Errorf("WithInitialSequence(%d): err = %v, want ErrInvalidInitialSequence", seq, err)
+		}
+	}
+}
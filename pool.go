@@ -0,0 +1,153 @@
+package uid64
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+)
+
+var ErrInvalidPoolSize = errors.New("pool size must be positive")
+
+// GeneratorPool spreads NextID calls over several generators with distinct
+// node IDs, multiplying the IDs that can be issued per millisecond.
+type GeneratorPool struct {
+	next       uint32
+	generators []*Generator
+}
+
+// NewGeneratorPool returns a pool of size generators configured by opts. The
+// generators get consecutive node IDs starting at the one set by WithNodeID,
+// or at 0, so the pool occupies node IDs [base, base+size). With WithExpvar,
+// each generator publishes its Stats under the name followed by a dot and its
+// node ID.
+func NewGeneratorPool(size int, opts ...GeneratorOption) (*GeneratorPool, error) {
+	if size < 1 {
+		return nil, ErrInvalidPoolSize
+	}
+	template, err := newGenerator(opts...)
+	if err != nil {
+		return nil, err
+	}
+	var base int
+	if template.nodeIDResolved == 1 {
+		base = template.nodeID
+	}
+
+	names := make([]string, size)
+	if template.expvarName != "" {
+		for i := range names {
+			names[i] = fmt.Sprintf("%s.%d", template.expvarName, base+i)
+		}
+		// Check every name before publishing any, since a published name
+		// cannot be withdrawn if a later one is taken.
+		expvarLock.Lock()
+		for _, name := range names {
+			if expvar.Get(name) != nil {
+				expvarLock.Unlock()
+				return nil, ErrExpvarNameInUse
+			}
+		}
+		expvarLock.Unlock()
+	}
+
+	p := &GeneratorPool{generators: make([]*Generator, size)}
+	for i := range p.generators {
+		g, err := NewWithOptions(append(opts[:len(opts):len(opts)], WithNodeID(base+i), WithExpvar(names[i]))...)
+		if err != nil {
+			p.Close()
+			return nil, err
+		}
+		p.generators[i] = g
+	}
+	return p, nil
+}
+
+// NextID returns an ID from the next generator in round-robin order. IDs are
+// unique across the pool but, within a millisecond, not ordered by call.
+func (p *GeneratorPool) NextID() (ID, error) {
+	i := atomic.AddUint32(&p.next, 1) % uint32(len(p.generators))
+	return p.generators[i].NextID()
+}
+
+// Close releases the node IDs of the pool's generators.
+func (p *GeneratorPool) Close() error {
+	for _, g := range p.generators {
+		if g != nil {
+			g.Close()
+		}
+	}
+	return nil
+}
+
+// LocalPool is a GeneratorPool that hands each goroutine a generator of its
+// own through a sync.Pool, so that goroutines calling NextID in parallel
+// mostly touch different generators instead of contending for one. The
+// assignment is sticky only as far as sync.Pool keeps it: a goroutine usually
+// gets back the generator it last used on the same P, and a generator dropped
+// by the sync.Pool is handed out again round-robin. Generators are safe for
+// concurrent use, so sharing one now and then costs only contention.
+type LocalPool struct {
+	*GeneratorPool
+	local sync.Pool
+}
+
+// NewLocalPool returns a LocalPool of poolSize generators configured by opts,
+// with node IDs [baseNodeID, baseNodeID+poolSize).
+func NewLocalPool(baseNodeID int, poolSize int, opts ...GeneratorOption) (*LocalPool, error) {
+	pool, err := NewGeneratorPool(poolSize, append(opts[:len(opts):len(opts)], WithNodeID(baseNodeID))...)
+	if err != nil {
+		return nil, err
+	}
+	p := &LocalPool{GeneratorPool: pool}
+	p.local.New = func() interface{} {
+		i := atomic.AddUint32(&pool.next, 1) % uint32(len(pool.generators))
+		return pool.generators[i]
+	}
+	return p, nil
+}
+
+// NextID returns an ID from the calling goroutine's generator. IDs are unique
+// across the pool but, within a millisecond, not ordered by call.
+func (p *LocalPool) NextID() (ID, error) {
+	g := p.local.Get().(*Generator)
+	id, err := g.NextID()
+	p.local.Put(g)
+	return id, err
+}
+
+// ShardedGenerator is a GeneratorPool that picks the generator by a key, such
+// as a tenant ID, rather than round-robin, so that each key always draws on
+// the sequence of the same shard and busy keys contend only with keys that
+// share their shard.
+type ShardedGenerator struct {
+	*GeneratorPool
+}
+
+// NewShardedGenerator returns a ShardedGenerator of shards generators
+// configured by opts, with consecutive node IDs as in NewGeneratorPool.
+func NewShardedGenerator(shards int, opts ...GeneratorOption) (*ShardedGenerator, error) {
+	pool, err := NewGeneratorPool(shards, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ShardedGenerator{GeneratorPool: pool}, nil
+}
+
+// ShardOf returns the shard, in [0, shards), that NextIDForKey uses for key.
+// It hashes key with 64 bit FNV-1a, so the mapping is stable across processes
+// with the same number of shards.
+func (s *ShardedGenerator) ShardOf(key []byte) int {
+	h := fnv.New64a()
+	h.Write(key)
+	return int(h.Sum64() % uint64(len(s.generators)))
+}
+
+// NextIDForKey returns an ID from the generator of key's shard. IDs for one
+// key are ordered by call; IDs for keys on different shards are unique but,
+// within a millisecond, not ordered.
+func (s *ShardedGenerator) NextIDForKey(key []byte) (ID, error) {
+	return s.generators[s.ShardOf(key)].NextID()
+}
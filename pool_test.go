@@ -0,0 +1,196 @@
+package uid64_test
+
+import (
+	"expvar"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestGeneratorPool(t *testing.T) {
+	pool, err := uid64.NewGeneratorPool(4, uid64.WithNodeID(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	seen := make(map[uid64.ID]bool)
+	nodes := make(map[int]bool)
+	for i := 0; i < 10000; i++ {
+		id, err := pool.NextID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[id] {
+			t.Fatalf("duplicate id %d", id)
+		}
+		seen[id] = true
+		nodes[uid64.NodeIDOf(int64(id))] = true
+	}
+	for nodeID := 100; nodeID < 104; nodeID++ {
+		if !nodes[nodeID] {
+			t.Errorf("node %d never used", nodeID)
+		}
+	}
+	if len(nodes) != 4 {
+		t.Errorf("used %d nodes, want 4", len(nodes))
+	}
+}
+
+func TestNewGeneratorPoolErrors(t *testing.T) {
+	if _, err := uid64.NewGeneratorPool(0); err != uid64.ErrInvalidPoolSize {
+		t.Errorf("size 0: err = %v, want ErrInvalidPoolSize", err)
+	}
+	if _, err := uid64.NewGeneratorPool(2, uid64.WithNodeID(1023)); err != uid64.ErrOutOfBoundNodeID {
+		t.Errorf("past the last node ID: err = %v, want ErrOutOfBoundNodeID", err)
+	}
+}
+
+func TestPoolExpvar(t *testing.T) {
+	pool, err := uid64.NewGeneratorPool(2, uid64.WithNodeID(400), uid64.WithExpvar("uid64_pool_test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	sharded, err := uid64.NewShardedGenerator(2, uid64.WithNodeID(410), uid64.WithExpvar("uid64_pool_test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sharded.Close()
+	local, err := uid64.NewLocalPool(420, 2, uid64.WithExpvar("uid64_pool_test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer local.Close()
+	if _, err := pool.NextID(); err != nil {
+		t.Fatal(err)
+	}
+	for _, nodeID := range []int{400, 401, 410, 411, 420, 421} {
+		name := fmt.Sprintf("uid64_pool_test.%d", nodeID)
+		if _, ok := expvar.Get(name).(*expvar.Map); !ok {
+			t.Errorf("expvar.Get(%q) = %T, want *expvar.Map", name, expvar.Get(name))
+		}
+	}
+
+	// A pool whose names are partly taken fails without publishing the rest.
+	if _, err := uid64.NewGeneratorPool(2, uid64.WithNodeID(401), uid64.WithExpvar("uid64_pool_test")); err != uid64.ErrExpvarNameInUse {
+		t.Errorf("pool over a published name: err = %v, want ErrExpvarNameInUse", err)
+	}
+	if v := expvar.Get("uid64_pool_test.402"); v != nil {
+		t.Errorf("failed pool published uid64_pool_test.402")
+	}
+}
+
+func TestLocalPool(t *testing.T) {
+	pool, err := uid64.NewLocalPool(100, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	const goroutines, perGoroutine = 8, 1000
+	ids := make(chan int64, goroutines*perGoroutine)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perGoroutine; j++ {
+				id, err := pool.NextID()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				ids <- int64(id)
+			}
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[int64]bool)
+	for id := range ids {
+		if seen[id] {
+			t.Fatalf("duplicate ID %d", id)
+		}
+		seen[id] = true
+		if n := uid64.NodeIDOf(id); n < 100 || n >= 104 {
+			t.Fatalf("NodeIDOf(%d) = %d, want 100 to 103", id, n)
+		}
+	}
+	if len(seen) != goroutines*perGoroutine {
+		t.Errorf("got %d IDs, want %d", len(seen), goroutines*perGoroutine)
+	}
+
+	if _, err := uid64.NewLocalPool(0, 0); err != uid64.ErrInvalidPoolSize {
+		t.Errorf("size 0: err = %v, want ErrInvalidPoolSize", err)
+	}
+}
+
+func TestShardedGenerator(t *testing.T) {
+	gen, err := uid64.NewShardedGenerator(4, uid64.WithNodeID(200))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+
+	used := make(map[int]bool)
+	for i := 0; i < 64; i++ {
+		key := []byte(fmt.Sprintf("tenant-%d", i))
+		shard := gen.ShardOf(key)
+		if shard < 0 || shard >= 4 || gen.ShardOf(key) != shard {
+			t.Fatalf("ShardOf(%q) = %d, want a stable shard in [0, 4)", key, shard)
+		}
+		used[shard] = true
+		var prev uid64.ID
+		for j := 0; j < 3; j++ {
+			id, err := gen.NextIDForKey(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n := uid64.NodeIDOf(int64(id)); n != 200+shard {
+				t.Fatalf("NodeIDOf(NextIDForKey(%q)) = %d, want %d", key, n, 200+shard)
+			}
+			if id <= prev {
+				t.Fatalf("NextIDForKey(%q) = %d after %d, want increasing", key, id, prev)
+			}
+			prev = id
+		}
+	}
+	if len(used) != 4 {
+		t.Errorf("64 keys used %d of 4 shards", len(used))
+	}
+
+	if _, err := uid64.NewShardedGenerator(0); err != uid64.ErrInvalidPoolSize {
+		t.Errorf("0 shards: err = %v, want ErrInvalidPoolSize", err)
+	}
+}
+
+// Run with -cpu 32 to compare the pools against BenchmarkParallel, which
+// drives a single generator.
+func BenchmarkGeneratorPool(b *testing.B) {
+	pool, err := uid64.NewGeneratorPool(16)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer pool.Close()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			pool.NextID()
+		}
+	})
+}
+
+func BenchmarkLocalPool(b *testing.B) {
+	pool, err := uid64.NewLocalPool(0, 32)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer pool.Close()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			pool.NextID()
+		}
+	})
+}
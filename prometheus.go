@@ -0,0 +1,147 @@
+//go:build prometheus
+// +build prometheus
+
+package uid64
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// prometheusHook is a StatsHook that updates Prometheus collectors.
+type prometheusHook struct {
+	g                   *Generator
+	generated           prometheus.Counter
+	sequenceExhaustions prometheus.Counter
+	clockRollbacks      prometheus.Counter
+	lastTimestamp       prometheus.Gauge
+	clockDivergence     prometheus.Gauge
+	nodeID              *prometheus.GaugeVec
+}
+
+// WithPrometheusMetrics registers collectors for the generator's Stats with
+// reg and keeps them up to date through a StatsHook. It is only available when
+// building with the prometheus tag. The collectors are registered once the
+// generator has been constructed, so a constructor that fails leaves reg
+// untouched. The metric names are fixed: generators registering with the same
+// reg, such as those of a GeneratorPool, share the collectors, which then add
+// up across them. To tell generators apart instead, wrap reg with
+// prometheus.WrapRegistererWith and distinguishing labels.
+func WithPrometheusMetrics(reg prometheus.Registerer) GeneratorOption {
+	return func(g *Generator) error {
+		h := &prometheusHook{
+			g: g,
+			generated: prometheus.NewCounter(prometheus.CounterOpts{
+				Name: "uid64_ids_generated_total",
+				Help: "Number of IDs generated.",
+			}),
+			sequenceExhaustions: prometheus.NewCounter(prometheus.CounterOpts{
+				Name: "uid64_sequence_exhaustions_total",
+				Help: "Number of times the sequence for a millisecond ran out.",
+			}),
+			clockRollbacks: prometheus.NewCounter(prometheus.CounterOpts{
+				Name: "uid64_clock_rollbacks_total",
+				Help: "Number of times the clock was seen going backwards.",
+			}),
+			lastTimestamp: prometheus.NewGauge(prometheus.GaugeOpts{
+				Name: "uid64_last_timestamp_seconds",
+				Help: "Unix time embedded in the last ID generated.",
+			}),
+			clockDivergence: prometheus.NewGauge(prometheus.GaugeOpts{
+				Name: "uid64_clock_divergence_seconds",
+				Help: "Lead of the wall clock over the monotonic clock, as last reported.",
+			}),
+			nodeID: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Name: "uid64_node_id",
+				Help: "Node ID derived by NewWithHostAutoDiscovery, labelled with the strategy that supplied it.",
+			}, []string{"strategy"}),
+		}
+		g.registerMetrics = append(g.registerMetrics, func() (func(), error) {
+			return h.register(reg)
+		})
+		return WithStatsHook(h)(g)
+	}
+}
+
+// register registers h's collectors with reg, switching to the collector
+// already registered in place of any of them, and returns a function that
+// unregisters the ones it added. If it fails, it adds none.
+func (h *prometheusHook) register(reg prometheus.Registerer) (func(), error) {
+	var added []prometheus.Collector
+	undo := func() {
+		for _, c := range added {
+			reg.Unregister(c)
+		}
+	}
+	share := func(c prometheus.Collector) (prometheus.Collector, error) {
+		err := reg.Register(c)
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return are.ExistingCollector, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		added = append(added, c)
+		return c, nil
+	}
+
+	for _, c := range []*prometheus.Counter{&h.generated, &h.sequenceExhaustions, &h.clockRollbacks} {
+		existing, err := share(*c)
+		if err != nil {
+			undo()
+			return nil, err
+		}
+		counter, ok := existing.(prometheus.Counter)
+		if !ok {
+			undo()
+			return nil, prometheus.AlreadyRegisteredError{ExistingCollector: existing, NewCollector: *c}
+		}
+		*c = counter
+	}
+	for _, c := range []*prometheus.Gauge{&h.lastTimestamp, &h.clockDivergence} {
+		existing, err := share(*c)
+		if err != nil {
+			undo()
+			return nil, err
+		}
+		gauge, ok := existing.(prometheus.Gauge)
+		if !ok {
+			undo()
+			return nil, prometheus.AlreadyRegisteredError{ExistingCollector: existing, NewCollector: *c}
+		}
+		*c = gauge
+	}
+	existing, err := share(h.nodeID)
+	if err != nil {
+		undo()
+		return nil, err
+	}
+	vec, ok := existing.(*prometheus.GaugeVec)
+	if !ok {
+		undo()
+		return nil, prometheus.AlreadyRegisteredError{ExistingCollector: existing, NewCollector: h.nodeID}
+	}
+	h.nodeID = vec
+	return undo, nil
+}
+
+func (h *prometheusHook) OnGenerate(id int64) {
+	h.generated.Inc()
+	// The layout and epoch are read here rather than when the option is
+	// applied, since options given after this one may change them.
+	ms := h.g.layout.timestamp(id) + h.g.epoch
+	h.lastTimestamp.Set(float64(ms) / 1000)
+}
+
+func (h *prometheusHook) OnSequenceExhaustion() {
+	h.sequenceExhaustions.Inc()
+}
+
+func (h *prometheusHook) OnClockRollback(delta int64) {
+	h.clockRollbacks.Inc()
+}
+
+func (h *prometheusHook) OnClockDivergence(delta int64) {
+	h.clockDivergence.Set(float64(delta) / 1000)
+}
+
+func (h *prometheusHook) OnNodeIDStrategy(name string, nodeID int) {
+	h.nodeID.WithLabelValues(name).Set(float64(nodeID))
+}
@@ -0,0 +1,131 @@
+//go:build prometheus
+// +build prometheus
+
+package uid64_test
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestWithPrometheusMetrics(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	now := int64(1000)
+	gen, err := uid64.NewWithOptions(
+		uid64.WithNodeID(1),
+		uid64.WithClock(func() int64 { return now }),
+		uid64.WithPrometheusMetrics(reg),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+	for i := 0; i < 1<<12; i++ {
+		if _, err := gen.NextID(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, ok := gen.TryNextID(); ok {
+		t.Fatal("TryNextID succeeded with the sequence exhausted")
+	}
+	now--
+	if _, ok := gen.TryNextID(); ok {
+		t.Fatal("TryNextID succeeded with the clock rolled back")
+	}
+
+	got := gather(t, reg)
+	want := map[string]float64{
+		"uid64_ids_generated_total":        1 << 12,
+		"uid64_sequence_exhaustions_total": 1,
+		"uid64_clock_rollbacks_total":      1,
+		"uid64_last_timestamp_seconds":     float64(uid64.TimeOf(1000<<22).UnixNano()) / 1e9,
+		"uid64_clock_divergence_seconds":   0,
+	}
+	for name, v := range want {
+		if got[name] != v {
+			t.Errorf("%s = %v, want %v", name, got[name], v)
+		}
+	}
+
+	// A second generator on the same registry shares the collectors.
+	other, err := uid64.NewWithOptions(uid64.WithNodeID(2), uid64.WithPrometheusMetrics(reg))
+	if err != nil {
+		t.Fatalf("second generator on the same registry: %v", err)
+	}
+	defer other.Close()
+	if _, err := other.NextID(); err != nil {
+		t.Fatal(err)
+	}
+	if got := gather(t, reg)["uid64_ids_generated_total"]; got != 1<<12+1 {
+		t.Errorf("uid64_ids_generated_total = %v, want %v", got, 1<<12+1)
+	}
+}
+
+func TestWithPrometheusMetricsFailedConstruction(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	if _, err := uid64.NewWithOptions(uid64.WithPrometheusMetrics(reg), uid64.WithMaxBatchSize(0)); err == nil {
+		t.Fatal("NewWithOptions with a bad option succeeded")
+	}
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(families) != 0 {
+		t.Errorf("failed construction left %d metric families registered", len(families))
+	}
+}
+
+func TestPoolPrometheusMetrics(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	pool, err := uid64.NewGeneratorPool(3, uid64.WithNodeID(300), uid64.WithPrometheusMetrics(reg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	sharded, err := uid64.NewShardedGenerator(2, uid64.WithNodeID(310), uid64.WithPrometheusMetrics(reg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sharded.Close()
+	local, err := uid64.NewLocalPool(320, 2, uid64.WithPrometheusMetrics(reg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer local.Close()
+
+	for i := 0; i < 10; i++ {
+		if _, err := pool.NextID(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := sharded.NextIDForKey([]byte{byte(i)}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := local.NextID(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := gather(t, reg)["uid64_ids_generated_total"]; got != 30 {
+		t.Errorf("uid64_ids_generated_total = %v, want 30", got)
+	}
+}
+
+func gather(t *testing.T, reg prometheus.Gatherer) map[string]float64 {
+	t.Helper()
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]float64)
+	for _, f := range families {
+		m := f.GetMetric()[0]
+		if c := m.GetCounter(); c != nil {
+			got[f.GetName()] = c.GetValue()
+		} else {
+			got[f.GetName()] = m.GetGauge().GetValue()
+		}
+	}
+	return got
+}
@@ -0,0 +1,119 @@
+package uid64
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrRangeInvalid = errors.New("time range is reversed or outside the epoch range")
+
+// IDsInTimeRange returns the smallest and largest IDs that can carry a
+// timestamp between start and end inclusive, at millisecond precision, for
+// use as bounds of a SQL BETWEEN clause. It assumes the default epoch and bit
+// layout, and returns ErrRangeInvalid if start is after end or either falls
+// outside the range representable by the timestamp field.
+func IDsInTimeRange(start, end time.Time) (minID, maxID int64, err error) {
+	if start.After(end) {
+		return 0, 0, ErrRangeInvalid
+	}
+	lo := unixMilli(start) - customEpoch
+	hi := unixMilli(end) - customEpoch
+	if lo < 0 || hi > maxTimestamp {
+		return 0, 0, ErrRangeInvalid
+	}
+	l := defaultLayout
+	return l.compose(lo, 0, 0), l.compose(hi, l.maxNodeID, l.maxSequence), nil
+}
+
+// IDRange is the closed interval of IDs [Lo, Hi], such as the IDs owned by a
+// shard. It is empty if Lo > Hi.
+type IDRange struct {
+	Lo, Hi int64
+}
+
+// emptyRange is the canonical empty IDRange.
+var emptyRange = IDRange{Lo: 0, Hi: -1}
+
+// IDRangeFromTimeRange returns the range of IDs that can carry a timestamp
+// between start and end inclusive, as computed by IDsInTimeRange, or an empty
+// range if IDsInTimeRange rejects the times.
+func IDRangeFromTimeRange(start, end time.Time) IDRange {
+	lo, hi, err := IDsInTimeRange(start, end)
+	if err != nil {
+		return emptyRange
+	}
+	return IDRange{Lo: lo, Hi: hi}
+}
+
+// IsEmpty reports whether r contains no IDs.
+func (r IDRange) IsEmpty() bool {
+	return r.Lo > r.Hi
+}
+
+// Contains reports whether id lies within r.
+func (r IDRange) Contains(id int64) bool {
+	return r.Lo <= id && id <= r.Hi
+}
+
+// Overlaps reports whether r and other have at least one ID in common.
+func (r IDRange) Overlaps(other IDRange) bool {
+	_, ok := r.Intersect(other)
+	return ok
+}
+
+// Intersect returns the IDs r and other have in common, and false if there
+// are none.
+func (r IDRange) Intersect(other IDRange) (IDRange, bool) {
+	in := r
+	if other.Lo > in.Lo {
+		in.Lo = other.Lo
+	}
+	if other.Hi < in.Hi {
+		in.Hi = other.Hi
+	}
+	if in.IsEmpty() {
+		return emptyRange, false
+	}
+	return in, true
+}
+
+// TimeRange returns the creation times of r's bounds, assuming the default
+// epoch and bit layout.
+func (r IDRange) TimeRange() (start, end time.Time) {
+	return TimeOf(r.Lo), TimeOf(r.Hi)
+}
+
+// IDIterator yields the IDs of a closed interval one at a time, for walking
+// a range too large to hold as a slice. It is not safe for concurrent use.
+type IDIterator struct {
+	next, hi int64
+	done     bool
+}
+
+// NewIDIterator returns an iterator over [lo, hi], which yields nothing if lo
+// is greater than hi.
+func NewIDIterator(lo, hi int64) *IDIterator {
+	return &IDIterator{next: lo, hi: hi, done: lo > hi}
+}
+
+// IDIteratorFromTimeRange returns an iterator over IDRangeFromTimeRange(start,
+// end). It yields nothing if the range is empty.
+func IDIteratorFromTimeRange(start, end time.Time) *IDIterator {
+	r := IDRangeFromTimeRange(start, end)
+	return NewIDIterator(r.Lo, r.Hi)
+}
+
+// Next returns the next ID in ascending order, or false once the interval is
+// exhausted.
+func (it *IDIterator) Next() (int64, bool) {
+	if it.done {
+		return 0, false
+	}
+	id := it.next
+	if id == it.hi {
+		it.done = true
+	} else {
+		it.next++
+	}
+	return id, true
+}
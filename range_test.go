@@ -0,0 +1,177 @@
+package uid64_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestIDsInTimeRange(t *testing.T) {
+	start := time.Now().Add(-time.Second)
+	id, err := g.NextInt64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	end := time.Now()
+
+	lo, hi, err := uid64.IDsInTimeRange(start, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id < lo || id > hi {
+		t.Errorf("id %d outside [%d, %d]", id, lo, hi)
+	}
+	if c := uid64.Decompose(lo); c.NodeID != 0 || c.Sequence != 0 || !c.Time.Equal(start.Truncate(time.Millisecond)) {
+		t.Errorf("Decompose(lo) = %+v", c)
+	}
+	if c := uid64.Decompose(hi); c.NodeID != 1023 || c.Sequence != 4095 || !c.Time.Equal(end.Truncate(time.Millisecond)) {
+		t.Errorf("Decompose(hi) = %+v", c)
+	}
+
+	_, earlierHi, err := uid64.IDsInTimeRange(start.Add(-time.Hour), start.Add(-time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if earlierHi >= lo {
+		t.Errorf("earlier range ends at %d, not before %d", earlierHi, lo)
+	}
+}
+
+func TestIDsInTimeRangeInvalid(t *testing.T) {
+	now := time.Now()
+	epoch := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, tc := range []struct{ start, end time.Time }{
+		{now, now.Add(-time.Millisecond)},
+		{epoch.Add(-time.Millisecond), now},
+		{now, epoch.Add(1 << 41 * time.Millisecond)},
+	} {
+		if _, _, err := uid64.IDsInTimeRange(tc.start, tc.end); err != uid64.ErrRangeInvalid {
+			t.Errorf("IDsInTimeRange(%v, %v): err = %v, want ErrRangeInvalid", tc.start, tc.end, err)
+		}
+	}
+}
+
+func TestIDRange(t *testing.T) {
+	epoch := uid64.TimeOf(0)
+	ms := epoch.Add(time.Hour)
+	single := uid64.IDRangeFromTimeRange(ms, ms)
+	full := uid64.IDRange{Lo: 0, Hi: 1<<63 - 1}
+	empty := uid64.IDRange{Lo: 10, Hi: 9}
+
+	for _, tc := range []struct {
+		name         string
+		r            uid64.IDRange
+		empty        bool
+		contains     []int64
+		not          []int64
+		start, end   time.Time
+		intersectors map[uid64.IDRange]bool
+	}{
+		{
+			name:  "empty",
+			r:     empty,
+			empty: true,
+			not:   []int64{9, 10},
+			start: uid64.TimeOf(10),
+			end:   uid64.TimeOf(9),
+			intersectors: map[uid64.IDRange]bool{
+				full:  false,
+				empty: false,
+			},
+		},
+		{
+			name:     "single millisecond",
+			r:        single,
+			contains: []int64{single.Lo, single.Hi, single.Lo + 1<<22 - 1},
+			not:      []int64{single.Lo - 1, single.Hi + 1},
+			start:    ms,
+			end:      ms,
+			intersectors: map[uid64.IDRange]bool{
+				full:                               true,
+				empty:                              false,
+				{Lo: single.Hi, Hi: single.Hi + 5}: true,
+				{Lo: single.Hi + 1, Hi: 1 << 62}:   false,
+			},
+		},
+		{
+			name:     "full",
+			r:        full,
+			contains: []int64{0, 1<<63 - 1},
+			not:      []int64{-1},
+			start:    epoch,
+			end:      uid64.TimeOf(1<<63 - 1),
+			intersectors: map[uid64.IDRange]bool{
+				full:   true,
+				single: true,
+				empty:  false,
+			},
+		},
+	} {
+		if got := tc.r.IsEmpty(); got != tc.empty {
+			t.Errorf("%s: IsEmpty = %v, want %v", tc.name, got, tc.empty)
+		}
+		for _, id := range tc.contains {
+			if !tc.r.Contains(id) {
+				t.Errorf("%s: Contains(%d) = false", tc.name, id)
+			}
+		}
+		for _, id := range tc.not {
+			if tc.r.Contains(id) {
+				t.Errorf("%s: Contains(%d) = true", tc.name, id)
+			}
+		}
+		if start, end := tc.r.TimeRange(); !start.Equal(tc.start) || !end.Equal(tc.end) {
+			t.Errorf("%s: TimeRange = %v, %v, want %v, %v", tc.name, start, end, tc.start, tc.end)
+		}
+		for other, want := range tc.intersectors {
+			if got := tc.r.Overlaps(other); got != want {
+				t.Errorf("%s: Overlaps(%+v) = %v, want %v", tc.name, other, got, want)
+			}
+			in, ok := tc.r.Intersect(other)
+			if ok != want || ok && (!tc.r.Contains(in.Lo) || !other.Contains(in.Hi) || in.IsEmpty()) {
+				t.Errorf("%s: Intersect(%+v) = %+v, %v", tc.name, other, in, ok)
+			}
+		}
+	}
+
+	if r := uid64.IDRangeFromTimeRange(ms, epoch); !r.IsEmpty() {
+		t.Errorf("IDRangeFromTimeRange of a reversed range = %+v, want empty", r)
+	}
+}
+
+func TestIDIterator(t *testing.T) {
+	collect := func(it *uid64.IDIterator) []int64 {
+		var ids []int64
+		for id, ok := it.Next(); ok; id, ok = it.Next() {
+			ids = append(ids, id)
+		}
+		return ids
+	}
+	if got, want := collect(uid64.NewIDIterator(5, 8)), []int64{5, 6, 7, 8}; !reflect.DeepEqual(got, want) {
+		t.Errorf("NewIDIterator(5, 8) yields %v, want %v", got, want)
+	}
+	if got := collect(uid64.NewIDIterator(8, 5)); got != nil {
+		t.Errorf("NewIDIterator(8, 5) yields %v, want nothing", got)
+	}
+	if got, want := collect(uid64.NewIDIterator(math.MaxInt64-1, math.MaxInt64)), []int64{math.MaxInt64 - 1, math.MaxInt64}; !reflect.DeepEqual(got, want) {
+		t.Errorf("iterating up to MaxInt64 yields %v, want %v", got, want)
+	}
+
+	start := uid64.TimeOf(1000 << 22)
+	it := uid64.IDIteratorFromTimeRange(start, start)
+	first, _ := it.Next()
+	last, n := first, 1
+	for id, ok := it.Next(); ok; id, ok = it.Next() {
+		last = id
+		n++
+	}
+	if first != 1000<<22 || last != 1001<<22-1 || n != 1<<22 {
+		t.Errorf("one millisecond yields %d IDs from %d to %d, want %d from %d to %d", n, first, last, 1<<22, 1000<<22, 1001<<22-1)
+	}
+	if _, ok := uid64.IDIteratorFromTimeRange(start.Add(time.Second), start).Next(); ok {
+		t.Error("a reversed time range yields IDs")
+	}
+}
@@ -0,0 +1,104 @@
+package uid64
+
+import (
+	"errors"
+	"io"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	ErrNodeIDInUse     = errors.New("node ID is already in use by another generator in this process")
+	ErrGeneratorClosed = errors.New("generator is closed")
+)
+
+var _ io.Closer = (*Generator)(nil)
+
+// registry tracks the node IDs held by generators in this process, so two
+// generators cannot silently issue the same IDs. It maps each node ID to the
+// token of the claim holding it rather than to the generator, so that a
+// generator dropped without Close can still be garbage collected; the
+// finalizer of its claim then releases the node ID.
+var registry = struct {
+	sync.Mutex
+	nodes     map[int]uint64
+	lastToken uint64
+}{nodes: make(map[int]uint64)}
+
+// claim is a generator's hold on a node ID in the registry. Nothing but the
+// generator refers to it, so it becomes unreachable with the generator even
+// when the generator is part of a reference cycle.
+type claim struct {
+	nodeID int
+	token  uint64
+}
+
+// release removes c from the registry if it still holds its node ID. The
+// caller must hold the registry lock.
+func (c *claim) release() {
+	if registry.nodes[c.nodeID] == c.token {
+		delete(registry.nodes, c.nodeID)
+	}
+}
+
+// finalize releases c once its generator has been collected without Close.
+func (c *claim) finalize() {
+	registry.Lock()
+	defer registry.Unlock()
+	c.release()
+}
+
+// DisableRegistry keeps the generator out of the process-wide node ID
+// registry, for callers that coordinate node IDs themselves or deliberately
+// run several generators on one node ID, as tests with fake clocks may.
+func DisableRegistry() GeneratorOption {
+	return func(g *Generator) error {
+		g.unregistered = true
+		return nil
+	}
+}
+
+// Close releases g's node ID so another generator in the process may use it,
+// and makes every later call that issues IDs fail with ErrGeneratorClosed.
+// Close is safe to call more than once and always returns nil. A generator
+// that is dropped without Close releases its node ID only once the garbage
+// collector has reclaimed it, which may be never, so close generators that
+// are done with rather than relying on that.
+func (g *Generator) Close() error {
+	registry.Lock()
+	defer registry.Unlock()
+	atomic.StoreUint32(&g.closed, 1)
+	if g.claim != nil {
+		runtime.SetFinalizer(g.claim, nil)
+		g.claim.release()
+		g.claim = nil
+	}
+	return nil
+}
+
+// register claims nodeID for g, unless g opted out with DisableRegistry.
+func (g *Generator) register(nodeID int) error {
+	if g.unregistered {
+		return nil
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	// Close sets closed under the registry lock, so a closed generator
+	// cannot claim a node ID after Close has released its own.
+	if atomic.LoadUint32(&g.closed) == 1 {
+		return ErrGeneratorClosed
+	}
+	if g.claim != nil && g.claim.nodeID == nodeID {
+		return nil
+	}
+	if _, ok := registry.nodes[nodeID]; ok {
+		return ErrNodeIDInUse
+	}
+	registry.lastToken++
+	c := &claim{nodeID: nodeID, token: registry.lastToken}
+	runtime.SetFinalizer(c, (*claim).finalize)
+	registry.nodes[nodeID] = c.token
+	g.claim = c
+	return nil
+}
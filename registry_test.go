@@ -0,0 +1,138 @@
+package uid64_test
+
+import (
+	"context"
+	"io"
+	"runtime"
+	"testing"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestRegistry(t *testing.T) {
+	gen, err := uid64.NewWithNodeID(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uid64.NewWithNodeID(5); err != uid64.ErrNodeIDInUse {
+		t.Errorf("second NewWithNodeID(5): err = %v, want ErrNodeIDInUse", err)
+	}
+	if _, err := uid64.NewWithDatacenterWorker(0, 5); err != uid64.ErrNodeIDInUse {
+		t.Errorf("NewWithDatacenterWorker(0, 5): err = %v, want ErrNodeIDInUse", err)
+	}
+	if _, err := uid64.NewGeneratorPool(2, uid64.WithNodeID(4)); err != uid64.ErrNodeIDInUse {
+		t.Errorf("pool over node 5: err = %v, want ErrNodeIDInUse", err)
+	}
+
+	disabled, err := uid64.NewWithOptions(uid64.WithNodeID(5), uid64.DisableRegistry())
+	if err != nil {
+		t.Fatalf("DisableRegistry: %v", err)
+	}
+	disabled.Close()
+
+	gen.Close()
+	gen.Close()
+	again, err := uid64.NewWithNodeID(5)
+	if err != nil {
+		t.Fatalf("NewWithNodeID(5) after Close: %v", err)
+	}
+	defer again.Close()
+
+	// The failed pool must have released node 4.
+	four, err := uid64.NewWithNodeID(4)
+	if err != nil {
+		t.Fatalf("NewWithNodeID(4): %v", err)
+	}
+	four.Close()
+}
+
+func TestRegistryDerivedNodeID(t *testing.T) {
+	strategy := uid64.NodeIDStrategyFunc(func(context.Context) (int, error) { return 6, nil })
+	first, err := uid64.NewWithOptions(uid64.WithNodeIDStrategy(strategy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+	if _, err := first.NextID(); err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := uid64.NewWithOptions(uid64.WithNodeIDStrategy(strategy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := second.NextID(); err != uid64.ErrNodeIDInUse {
+		t.Errorf("NextID with a derived node ID in use: err = %v, want ErrNodeIDInUse", err)
+	}
+	if got := second.NodeID(); got != -1 {
+		t.Errorf("NodeID = %d, want -1", got)
+	}
+}
+
+func TestRegistryReleasesCollectedGenerator(t *testing.T) {
+	func() {
+		gen, err := uid64.NewWithNodeID(11)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := gen.NextID(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	// The claim's finalizer runs on a later cycle than the one that finds
+	// the generator unreachable, so collect until the node ID is free.
+	for i := 0; i < 10; i++ {
+		runtime.GC()
+		gen, err := uid64.NewWithNodeID(11)
+		if err == nil {
+			gen.Close()
+			return
+		}
+		if err != uid64.ErrNodeIDInUse {
+			t.Fatal(err)
+		}
+	}
+	t.Fatal("node ID of a generator dropped without Close was never released")
+}
+
+func TestClose(t *testing.T) {
+	gen, err := uid64.NewWithNodeID(9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gen.NextID(); err != nil {
+		t.Fatal(err)
+	}
+	if err := io.Closer(gen).Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if _, err := gen.NextID(); err != uid64.ErrGeneratorClosed {
+		t.Errorf("NextID after Close: err = %v, want ErrGeneratorClosed", err)
+	}
+	if _, ok := gen.TryNextID(); ok {
+		t.Error("TryNextID succeeded after Close")
+	}
+	if _, err := gen.NextIDBatch(3); err != uid64.ErrGeneratorClosed {
+		t.Errorf("NextIDBatch after Close: err = %v, want ErrGeneratorClosed", err)
+	}
+	if err := gen.Health(); err != uid64.ErrGeneratorClosed {
+		t.Errorf("Health after Close: err = %v, want ErrGeneratorClosed", err)
+	}
+
+	// A generator closed before deriving its node ID must not claim one.
+	strategy := uid64.NodeIDStrategyFunc(func(context.Context) (int, error) { return 9, nil })
+	lazy, err := uid64.NewWithOptions(uid64.WithNodeIDStrategy(strategy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lazy.Close()
+	if got := lazy.NodeID(); got != -1 {
+		t.Errorf("NodeID after Close = %d, want -1", got)
+	}
+	again, err := uid64.NewWithNodeID(9)
+	if err != nil {
+		t.Fatalf("NewWithNodeID(9) after Close: %v", err)
+	}
+	again.Close()
+}
@@ -0,0 +1,40 @@
+package uid64
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// ShardOf maps id to one of shards partitions by taking it modulo shards. The
+// remainder depends only on the low bits of the ID when shards is a power of
+// two, and mostly on them otherwise, so the spread follows the sequence and
+// node ID rather than being random: at low rates most IDs have sequence 0 and
+// land on the same few shards. Where that matters, partition by time instead,
+// giving each shard a ShardRange. It panics if shards < 1.
+func ShardOf(id int64, shards int) int {
+	if shards < 1 {
+		panic(fmt.Sprintf("uid64: ShardOf with %d shards", shards))
+	}
+	return int(uint64(id) % uint64(shards))
+}
+
+// ShardRange splits the non-negative IDs into shards contiguous ranges of
+// nearly equal size and returns the one numbered shard. Since the timestamp
+// occupies the high bits, each range covers a span of creation time, so this
+// is time-bucketed sharding; it is not the inverse of ShardOf. It panics if
+// shards < 1 or shard is not in [0, shards).
+func ShardRange(shard, shards int) IDRange {
+	if shards < 1 || shard < 0 || shard >= shards {
+		panic(fmt.Sprintf("uid64: ShardRange of shard %d of %d", shard, shards))
+	}
+	return IDRange{Lo: shardBound(shard, shards), Hi: shardBound(shard+1, shards) - 1}
+}
+
+// shardBound returns floor(2^63 * i / shards), the first ID of shard i, or
+// math.MinInt64 standing in for 2^63 when i == shards, so that subtracting one
+// yields the largest ID.
+func shardBound(i, shards int) int64 {
+	hi, lo := bits.Mul64(1<<63, uint64(i))
+	q, _ := bits.Div64(hi, lo, uint64(shards))
+	return int64(q)
+}
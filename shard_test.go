@@ -0,0 +1,52 @@
+package uid64_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestShardOf(t *testing.T) {
+	for _, tc := range []struct {
+		id     int64
+		shards int
+		want   int
+	}{
+		{0, 1, 0},
+		{10, 3, 1},
+		{math.MaxInt64, 16, 15},
+		{-1, 10, 5}, // 2^64-1 mod 10
+	} {
+		if got := uid64.ShardOf(tc.id, tc.shards); got != tc.want {
+			t.Errorf("ShardOf(%d, %d) = %d, want %d", tc.id, tc.shards, got, tc.want)
+		}
+	}
+}
+
+func TestShardRange(t *testing.T) {
+	for _, shards := range []int{1, 3, 7, 1024} {
+		next := int64(0)
+		for shard := 0; shard < shards; shard++ {
+			r := uid64.ShardRange(shard, shards)
+			if r.Lo != next || r.IsEmpty() {
+				t.Fatalf("ShardRange(%d, %d) = %+v, want to start at %d", shard, shards, r, next)
+			}
+			next = r.Hi + 1
+		}
+		if last := uid64.ShardRange(shards-1, shards); last.Hi != math.MaxInt64 {
+			t.Errorf("last of %d shards ends at %d, want MaxInt64", shards, last.Hi)
+		}
+	}
+
+	for _, tc := range [][2]int{{0, 0}, {-1, 4}, {4, 4}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("ShardRange(%d, %d) did not panic", tc[0], tc[1])
+				}
+			}()
+			uid64.ShardRange(tc[0], tc[1])
+		}()
+	}
+}
@@ -0,0 +1,26 @@
+package uid64
+
+import "errors"
+
+var ErrSnowflakeTooOld = errors.New("Twitter Snowflake ID predates the uid64 epoch")
+
+// Snowflake is an ID in the Twitter Snowflake format: a 41 bit millisecond
+// timestamp since the Twitter epoch, then a 10 bit machine ID and a 12 bit
+// sequence, the same split as uid64 uses below its timestamp.
+type Snowflake = int64
+
+// twitterEpoch is the Twitter Snowflake epoch, 2010-11-04T01:42:54.657Z.
+const twitterEpoch = int64(1288834974657)
+
+// FromTwitterSnowflake converts id to a uid64 ID with the same creation time
+// and machine ID, rebased to the default epoch, for migrating stored IDs. The
+// sequence is not carried over, so the result is 0 in the sequence field and
+// only approximately preserves the order of IDs from the same millisecond.
+// It returns ErrSnowflakeTooOld if id was created before the uid64 epoch.
+func FromTwitterSnowflake(id Snowflake) (int64, error) {
+	ts := defaultLayout.timestamp(id) + twitterEpoch - customEpoch
+	if ts < 0 {
+		return 0, ErrSnowflakeTooOld
+	}
+	return defaultLayout.compose(ts, defaultLayout.nodeID(id), 0), nil
+}
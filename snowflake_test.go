@@ -0,0 +1,38 @@
+package uid64_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestFromTwitterSnowflake(t *testing.T) {
+	// A Snowflake from 2021-02-10T15:36:18.766Z.
+	const tweet = uid64.Snowflake(1359526616301694976)
+	id, err := uid64.FromTwitterSnowflake(tweet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2021, 2, 10, 15, 36, 18, 766e6, time.UTC)
+	c := uid64.Decompose(id)
+	if !c.Time.Equal(want) {
+		t.Errorf("Time = %v, want %v", c.Time, want)
+	}
+	if c.NodeID != int(tweet>>12&1023) || c.Sequence != 0 {
+		t.Errorf("NodeID, Sequence = %d, %d, want %d, 0", c.NodeID, c.Sequence, tweet>>12&1023)
+	}
+
+	later, err := uid64.FromTwitterSnowflake(tweet + 1<<22)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if later <= id {
+		t.Errorf("a later Snowflake converted to %d, not after %d", later, id)
+	}
+
+	// 2012, before the uid64 epoch.
+	if _, err := uid64.FromTwitterSnowflake(200000000000000000); err != uid64.ErrSnowflakeTooOld {
+		t.Errorf("err = %v, want ErrSnowflakeTooOld", err)
+	}
+}
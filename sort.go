@@ -0,0 +1,59 @@
+package uid64
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SortIDs sorts ids into creation order. Since the timestamp occupies the high
+// bits this is a numeric sort, done on the unsigned value so that an ID with
+// the sign bit set sorts last, as it does in every Encoding.
+func SortIDs(ids []int64) {
+	sort.Slice(ids, func(i, j int) bool { return uint64(ids[i]) < uint64(ids[j]) })
+}
+
+// IsSorted reports whether ids are in the order SortIDs puts them in.
+func IsSorted(ids []int64) bool {
+	return sort.SliceIsSorted(ids, func(i, j int) bool { return uint64(ids[i]) < uint64(ids[j]) })
+}
+
+// SortStrings sorts IDs encoded with enc into creation order without decoding
+// them. Every Encoding but base64url is fixed-width with its alphabet in ASCII
+// order, so a plain string sort suffices for them; base64url strings are
+// compared by the position of their characters in the base64url alphabet.
+// Either way the strings must be as Encode produced them: padded to full
+// width and, for base32, in upper case. It panics if enc is not one of the
+// Encoding constants.
+func SortStrings(ids []string, enc Encoding) {
+	if _, ok := codecs[enc]; !ok {
+		panic(fmt.Sprintf("uid64: unknown encoding %q", string(enc)))
+	}
+	if enc == EncodingBase64URL {
+		sort.Slice(ids, func(i, j int) bool { return base64URLLess(ids[i], ids[j]) })
+		return
+	}
+	sort.Strings(ids)
+}
+
+// base64URLRank maps each character of the base64url alphabet to its
+// position in it, and every other byte past the end of it.
+var base64URLRank = func() (rank [256]int) {
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+	for b := range rank {
+		rank[b] = len(alphabet) + b
+	}
+	for i := 0; i < len(alphabet); i++ {
+		rank[alphabet[i]] = i
+	}
+	return rank
+}()
+
+// base64URLLess reports whether the base64url string a sorts before b.
+func base64URLLess(a, b string) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if ra, rb := base64URLRank[a[i]], base64URLRank[b[i]]; ra != rb {
+			return ra < rb
+		}
+	}
+	return len(a) < len(b)
+}
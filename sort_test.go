@@ -0,0 +1,78 @@
+package uid64_test
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestSortIDs(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	gen := newTestGenerator(t, clock.Now)
+	var want []int64
+	for i := 0; i < 100; i++ {
+		id, err := gen.NextInt64()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, id)
+		clock.now += int64(i % 3)
+	}
+	want = append(want, -1)
+
+	ids := append([]int64(nil), want...)
+	rand.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+	if uid64.IsSorted(ids) {
+		t.Fatal("IsSorted of shuffled IDs = true")
+	}
+	uid64.SortIDs(ids)
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("SortIDs = %v, want %v", ids, want)
+	}
+	if !uid64.IsSorted(ids) {
+		t.Error("IsSorted after SortIDs = false")
+	}
+
+	for _, enc := range allEncodings {
+		strs := make([]string, len(want))
+		for i, id := range want {
+			strs[i] = uid64.Encode(id, enc)
+		}
+		sorted := append([]string(nil), strs...)
+		rand.Shuffle(len(sorted), func(i, j int) { sorted[i], sorted[j] = sorted[j], sorted[i] })
+		uid64.SortStrings(sorted, enc)
+		if !reflect.DeepEqual(sorted, strs) {
+			t.Errorf("SortStrings(%s) = %v, want %v", enc, sorted, strs)
+		}
+	}
+}
+
+var allEncodings = []uid64.Encoding{
+	uid64.EncodingBase62, uid64.EncodingBase32Crockford, uid64.EncodingHex, uid64.EncodingBase58, uid64.EncodingBase64URL,
+}
+
+func TestSortStringsAcrossAlphabet(t *testing.T) {
+	// These IDs differ in the leading character of every encoding, so they
+	// sort correctly only if the whole alphabet is ranked in order.
+	ids := []int64{52 << 52, 1 << 52, 62 << 52, 3}
+	for _, enc := range allEncodings {
+		strs := make([]string, len(ids))
+		for i, id := range ids {
+			strs[i] = uid64.Encode(id, enc)
+		}
+		uid64.SortStrings(strs, enc)
+		got := make([]int64, len(strs))
+		for i, s := range strs {
+			id, err := uid64.Decode(s, enc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got[i] = id
+		}
+		if !uid64.IsSorted(got) {
+			t.Errorf("SortStrings(%s) gave IDs %v, want them sorted", enc, got)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package uid64_test
+
+import (
+	"encoding"
+	"testing"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*uid64.Generator)(nil)
+	_ encoding.BinaryUnmarshaler = (*uid64.Generator)(nil)
+)
+
+func TestNewFromState(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	gen := newTestGenerator(t, clock.Now)
+	var last int64
+	for i := 0; i < 10; i++ {
+		id, err := gen.NextInt64()
+		if err != nil {
+			t.Fatal(err)
+		}
+		last = id
+	}
+	state, err := gen.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state) != 8 {
+		t.Fatalf("len(state) = %d, want 8", len(state))
+	}
+	gen.Close()
+
+	restored, err := uid64.NewFromState(1, state, uid64.WithClock(clock.Now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restored.Close()
+	id, err := restored.NextInt64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id <= last {
+		t.Errorf("restored NextID = %d, want greater than %d", id, last)
+	}
+	if c := uid64.Decompose(id); c.Timestamp != 1000 || c.Sequence != 10 {
+		t.Errorf("Decompose = %+v, want timestamp 1000 sequence 10", c)
+	}
+}
+
+func TestNewFromStateErrors(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	gen := newTestGenerator(t, clock.Now)
+	if _, err := gen.NextID(); err != nil {
+		t.Fatal(err)
+	}
+	state, err := gen.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	past := &fakeClock{now: 999}
+	if _, err := uid64.NewFromState(1, state, uid64.WithClock(past.Now)); err != uid64.ErrStateInFuture {
+		t.Errorf("err = %v, want ErrStateInFuture", err)
+	}
+	if _, err := uid64.NewFromState(1, state[:7]); err != uid64.ErrInvalidStateData {
+		t.Errorf("err = %v, want ErrInvalidStateData", err)
+	}
+	if _, err := uid64.NewFromState(-1, state); err != uid64.ErrOutOfBoundNodeID {
+		t.Errorf("err = %v, want ErrOutOfBoundNodeID", err)
+	}
+}
+
+func TestReset(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	gen := newTestGenerator(t, clock.Now)
+	if _, err := gen.NextID(); err != nil {
+		t.Fatal(err)
+	}
+
+	gen.Reset()
+	if got := gen.LastTimestamp(); got != -1 {
+		t.Errorf("LastTimestamp after Reset = %d, want -1", got)
+	}
+	clock.now = 500
+	id, err := gen.NextInt64()
+	if err != nil {
+		t.Fatalf("NextID after Reset with an earlier clock: %v", err)
+	}
+	if c := uid64.Decompose(id); c.Timestamp != 500 || c.Sequence != 0 || c.NodeID != 1 {
+		t.Errorf("Decompose = %+v, want timestamp 500 sequence 0 node 1", c)
+	}
+}
@@ -0,0 +1,171 @@
+package uid64
+
+import "sync/atomic"
+
+// Stats is a snapshot of how hard a Generator is being driven.
+type Stats struct {
+	// TotalGenerated counts the IDs produced, including those in batches.
+	TotalGenerated int64
+	// SequenceExhaustions counts the times a caller found the sequence for
+	// the current millisecond used up and had to wait or give up.
+	SequenceExhaustions int64
+	// ClockRollbacks counts the times the clock was seen going backwards.
+	ClockRollbacks int64
+	// LastTimestamp is the timestamp of the last ID, as by LastTimestamp.
+	LastTimestamp int64
+}
+
+// Stats returns a snapshot of g's counters. It takes no lock, so the fields
+// are read one at a time and may be mutually inconsistent under load.
+func (g *Generator) Stats() Stats {
+	return Stats{
+		TotalGenerated:      atomic.LoadInt64(&g.totalGenerated),
+		SequenceExhaustions: atomic.LoadInt64(&g.sequenceExhaustions),
+		ClockRollbacks:      atomic.LoadInt64(&g.clockRollbacks),
+		LastTimestamp:       g.LastTimestamp(),
+	}
+}
+
+// Sequence returns the sequence number of the last ID g produced in the
+// current millisecond, or 0 if it has produced none in it. The sequence resets
+// to 0 each new millisecond, so a value that is regularly close to the maximum
+// means g is near its per-millisecond capacity and more nodes are needed.
+func (g *Generator) Sequence() int64 {
+	sequence, _ := g.currentSequence()
+	return sequence
+}
+
+// Saturation returns Sequence as a fraction of the largest sequence number,
+// between 0 and 1. With no sequence bits, as under WithBitLayout(22, 0), each
+// millisecond holds one ID, so Saturation is 1 once it has been issued.
+func (g *Generator) Saturation() float64 {
+	sequence, ok := g.currentSequence()
+	switch {
+	case !ok:
+		return 0
+	case g.layout.maxSequence == 0:
+		return 1
+	}
+	return float64(sequence) / float64(g.layout.maxSequence)
+}
+
+// currentSequence returns the sequence of the last ID g produced and whether
+// it was produced in the current millisecond.
+func (g *Generator) currentSequence() (int64, bool) {
+	lastTimestamp, sequence := g.layout.unpackState(atomic.LoadUint64(&g.state))
+	if lastTimestamp < g.clock() {
+		return 0, false
+	}
+	return sequence, true
+}
+
+// MaxIDsPerSecond returns the most IDs a single node can produce per second
+// with the default bit layout: one full sequence every millisecond, or
+// 4,096,000.
+func MaxIDsPerSecond() int {
+	return (maxSequence + 1) * 1000
+}
+
+// MaxIDsPerSecondTotal returns MaxIDsPerSecond for a deployment of the given
+// number of nodes.
+func MaxIDsPerSecondTotal(nodes int) int {
+	return MaxIDsPerSecond() * nodes
+}
+
+// StatsHook receives generator events as they happen, for feeding a metrics
+// library. Its methods are called on the goroutine that caused the event,
+// with no lock held, so they may be called concurrently; they should not
+// block.
+type StatsHook interface {
+	// OnGenerate is called for every ID produced, including those in batches.
+	OnGenerate(id int64)
+	// OnSequenceExhaustion is called whenever SequenceExhaustions is counted.
+	OnSequenceExhaustion()
+	// OnClockRollback is called whenever ClockRollbacks is counted, with the
+	// number of milliseconds the clock went backwards.
+	OnClockRollback(delta int64)
+	// OnClockDivergence is called when the wall clock moves away from the
+	// monotonic clock of WithMonotonicClock, with how many milliseconds the
+	// wall clock is ahead, or behind if negative.
+	OnClockDivergence(delta int64)
+}
+
+// NodeIDStrategyObserver may be implemented by a StatsHook to learn which
+// strategy a generator made by NewWithHostAutoDiscovery derived its node ID
+// from. OnNodeIDStrategy is called with the strategy's name, "env",
+// "hostname-ordinal", "machine-id", "mac" or "random", and the node ID.
+type NodeIDStrategyObserver interface {
+	OnNodeIDStrategy(name string, nodeID int)
+}
+
+// NoopStatsHook ignores all events. It is the default StatsHook.
+type NoopStatsHook struct{}
+
+func (NoopStatsHook) OnGenerate(id int64)           {}
+func (NoopStatsHook) OnSequenceExhaustion()         {}
+func (NoopStatsHook) OnClockRollback(delta int64)   {}
+func (NoopStatsHook) OnClockDivergence(delta int64) {}
+
+type multiStatsHook []StatsHook
+
+// MultiStatsHook returns a StatsHook that passes every event to each of hooks
+// in turn.
+func MultiStatsHook(hooks ...StatsHook) StatsHook {
+	return multiStatsHook(append([]StatsHook(nil), hooks...))
+}
+
+func (m multiStatsHook) OnGenerate(id int64) {
+	for _, h := range m {
+		h.OnGenerate(id)
+	}
+}
+
+func (m multiStatsHook) OnSequenceExhaustion() {
+	for _, h := range m {
+		h.OnSequenceExhaustion()
+	}
+}
+
+func (m multiStatsHook) OnClockRollback(delta int64) {
+	for _, h := range m {
+		h.OnClockRollback(delta)
+	}
+}
+
+func (m multiStatsHook) OnClockDivergence(delta int64) {
+	for _, h := range m {
+		h.OnClockDivergence(delta)
+	}
+}
+
+func (m multiStatsHook) OnNodeIDStrategy(name string, nodeID int) {
+	for _, h := range m {
+		if o, ok := h.(NodeIDStrategyObserver); ok {
+			o.OnNodeIDStrategy(name, nodeID)
+		}
+	}
+}
+
+// WithStatsHook registers hook to receive the generator's events. It may be
+// given more than once; the hooks are combined as by MultiStatsHook.
+func WithStatsHook(hook StatsHook) GeneratorOption {
+	return func(g *Generator) error {
+		if g.statsHook == nil {
+			g.statsHook = hook
+		} else {
+			g.statsHook = MultiStatsHook(g.statsHook, hook)
+		}
+		return nil
+	}
+}
+
+func (g *Generator) countSequenceExhaustion() {
+	atomic.AddInt64(&g.sequenceExhaustions, 1)
+	g.statsHook.OnSequenceExhaustion()
+}
+
+func (g *Generator) countClockRollback(delta int64) {
+	atomic.AddInt64(&g.clockRollbacks, 1)
+	g.breaker.recordRollback()
+	g.statsHook.OnClockRollback(delta)
+}
@@ -0,0 +1,203 @@
+package uid64_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestStats(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	gen := newTestGenerator(t, clock.Now)
+
+	for i := 0; i < 4096; i++ {
+		if _, err := gen.NextID(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, ok := gen.TryNextID(); ok {
+		t.Fatal("TryNextID succeeded with the sequence exhausted")
+	}
+	clock.now++
+	if _, err := gen.NextIDBatch(10); err != nil {
+		t.Fatal(err)
+	}
+	clock.now -= 2
+	if _, err := gen.NextID(); !errors.Is(err, uid64.ErrInvalidState) {
+		t.Fatalf("err = %v, want ErrInvalidState", err)
+	}
+
+	want := uid64.Stats{
+		TotalGenerated:      4106,
+		SequenceExhaustions: 1,
+		ClockRollbacks:      1,
+		LastTimestamp:       1001,
+	}
+	if got := gen.Stats(); got != want {
+		t.Errorf("Stats = %+v, want %+v", got, want)
+	}
+}
+
+func TestSequence(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	gen := newTestGenerator(t, clock.Now)
+	if got := gen.Sequence(); got != 0 {
+		t.Errorf("Sequence before NextID = %d, want 0", got)
+	}
+
+	for i := 0; i <= 2047; i++ {
+		if _, err := gen.NextID(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := gen.Sequence(); got != 2047 {
+		t.Errorf("Sequence = %d, want 2047", got)
+	}
+	if got, want := gen.Saturation(), 2047.0/4095; got != want {
+		t.Errorf("Saturation = %v, want %v", got, want)
+	}
+
+	clock.now++
+	if _, err := gen.NextID(); err != nil {
+		t.Fatal(err)
+	}
+	if got := gen.Sequence(); got != 0 {
+		t.Errorf("Sequence in the next millisecond = %d, want 0", got)
+	}
+	if got := gen.Saturation(); got != 0 {
+		t.Errorf("Saturation in the next millisecond = %v, want 0", got)
+	}
+
+	if _, err := gen.NextID(); err != nil {
+		t.Fatal(err)
+	}
+	clock.now++
+	if got := gen.Sequence(); got != 0 {
+		t.Errorf("Sequence with no ID in the current millisecond = %d, want 0", got)
+	}
+	if got := gen.Saturation(); got != 0 {
+		t.Errorf("Saturation with no ID in the current millisecond = %v, want 0", got)
+	}
+}
+
+func TestSaturationWithoutSequenceBits(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	gen, err := uid64.NewWithOptions(uid64.WithNodeID(1), uid64.WithClock(clock.Now), uid64.WithBitLayout(22, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+	if got := gen.Saturation(); got != 0 {
+		t.Errorf("Saturation before NextID = %v, want 0", got)
+	}
+	if _, err := gen.NextID(); err != nil {
+		t.Fatal(err)
+	}
+	if got := gen.Saturation(); got != 1 {
+		t.Errorf("Saturation after NextID = %v, want 1", got)
+	}
+	clock.now++
+	if got := gen.Saturation(); got != 0 {
+		t.Errorf("Saturation in the next millisecond = %v, want 0", got)
+	}
+}
+
+func TestMaxIDsPerSecond(t *testing.T) {
+	if got := uid64.MaxIDsPerSecond(); got != 4096000 {
+		t.Errorf("MaxIDsPerSecond = %d, want 4096000", got)
+	}
+	if got := uid64.MaxIDsPerSecondTotal(1024); got != 4096000*1024 {
+		t.Errorf("MaxIDsPerSecondTotal(1024) = %d, want %d", got, 4096000*1024)
+	}
+}
+
+type recordingHook struct {
+	generated   []int64
+	exhaustions int
+	rollbacks   []int64
+	strategies  []string
+}
+
+func (h *recordingHook) OnGenerate(id int64)           { h.generated = append(h.generated, id) }
+func (h *recordingHook) OnSequenceExhaustion()         { h.exhaustions++ }
+func (h *recordingHook) OnClockRollback(delta int64)   { h.rollbacks = append(h.rollbacks, delta) }
+func (h *recordingHook) OnClockDivergence(delta int64) {}
+
+// recordingHook also observes node ID strategies, which StatsHooks need not.
+var (
+	_ uid64.StatsHook              = uid64.NoopStatsHook{}
+	_ uid64.NodeIDStrategyObserver = (*recordingHook)(nil)
+)
+
+func (h *recordingHook) OnNodeIDStrategy(name string, nodeID int) {
+	h.strategies = append(h.strategies, name)
+}
+
+func TestWithStatsHook(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	hook := &recordingHook{}
+	gen, err := uid64.NewWithOptions(uid64.WithNodeID(1), uid64.WithClock(clock.Now), uid64.WithStatsHook(hook))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+
+	id, err := gen.NextInt64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch, err := gen.NextIDBatch(1<<12 - 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := gen.TryNextID(); ok {
+		t.Fatal("TryNextID succeeded with the sequence exhausted")
+	}
+	clock.now -= 3
+	if _, ok := gen.TryNextID(); ok {
+		t.Fatal("TryNextID succeeded with the clock rolled back")
+	}
+
+	if len(hook.generated) != 1<<12 || hook.generated[0] != id || hook.generated[1<<12-1] != batch[len(batch)-1] {
+		t.Errorf("OnGenerate saw %d IDs, want %d ending with the batch", len(hook.generated), 1<<12)
+	}
+	if hook.exhaustions != 1 {
+		t.Errorf("OnSequenceExhaustion called %d times, want 1", hook.exhaustions)
+	}
+	if len(hook.rollbacks) != 1 || hook.rollbacks[0] != 3 {
+		t.Errorf("OnClockRollback deltas = %v, want [3]", hook.rollbacks)
+	}
+}
+
+func TestMultiStatsHook(t *testing.T) {
+	first, second := &recordingHook{}, &recordingHook{}
+	clock := &fakeClock{now: 1000}
+	gen, err := uid64.NewWithOptions(
+		uid64.WithNodeID(1),
+		uid64.WithClock(clock.Now),
+		uid64.WithStatsHook(first),
+		uid64.WithStatsHook(uid64.NoopStatsHook{}),
+		uid64.WithStatsHook(second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+	if _, err := gen.NextID(); err != nil {
+		t.Fatal(err)
+	}
+	clock.now--
+	gen.TryNextID()
+	for i, h := range []*recordingHook{first, second} {
+		if len(h.generated) != 1 || len(h.rollbacks) != 1 {
+			t.Errorf("hook %d saw %d IDs and %d rollbacks, want 1 and 1", i, len(h.generated), len(h.rollbacks))
+		}
+	}
+
+	third := &recordingHook{}
+	uid64.MultiStatsHook(first, third).OnSequenceExhaustion()
+	if first.exhaustions != 1 || third.exhaustions != 1 {
+		t.Errorf("exhaustions = %d, %d, want 1, 1", first.exhaustions, third.exhaustions)
+	}
+}
@@ -0,0 +1,157 @@
+package uid64
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+)
+
+// ErrStrategyNotApplicable is returned by a NodeIDStrategy that has nothing to
+// go on in the current environment, such as EnvStrategy with its variable
+// unset. ChainedNodeIDStrategy moves on to the next strategy when it sees it.
+var ErrStrategyNotApplicable = errors.New("node ID strategy does not apply here")
+
+// notApplicable wraps the reason a strategy does not apply so that it matches
+// both ErrStrategyNotApplicable and the reason under errors.Is.
+type notApplicable struct {
+	err error
+}
+
+func (e notApplicable) Error() string {
+	return e.err.Error()
+}
+
+func (e notApplicable) Unwrap() error {
+	return e.err
+}
+
+func (e notApplicable) Is(target error) bool {
+	return target == ErrStrategyNotApplicable
+}
+
+// ChainedNodeIDStrategy returns a strategy that tries each of strategies in
+// order, skipping those that fail with ErrStrategyNotApplicable. Any other
+// error stops the chain and is returned. If no strategy applies, it returns
+// ErrStrategyNotApplicable.
+func ChainedNodeIDStrategy(strategies ...NodeIDStrategy) NodeIDStrategy {
+	strategies = append([]NodeIDStrategy(nil), strategies...)
+	return NodeIDStrategyFunc(func(ctx context.Context) (int, error) {
+		for _, s := range strategies {
+			if err := ctx.Err(); err != nil {
+				return 0, err
+			}
+			nodeID, err := s.NodeID(ctx)
+			if errors.Is(err, ErrStrategyNotApplicable) {
+				continue
+			}
+			return nodeID, err
+		}
+		return 0, ErrStrategyNotApplicable
+	})
+}
+
+// The built-in strategies derive node IDs for the default bit layout, in the
+// range 0 to 1023.
+var (
+	// MACStrategy hashes the MAC addresses of the host's network interfaces,
+	// as the default strategy does, but does not apply rather than falling
+	// back to a random node ID if there are none.
+	MACStrategy NodeIDStrategy = NodeIDStrategyFunc(macStrategy)
+	// HostnameOrdinalStrategy uses the StatefulSet pod ordinal in the host
+	// name, as NodeFromKubernetesStatefulSet. It does not apply if the host
+	// name has no ordinal.
+	HostnameOrdinalStrategy NodeIDStrategy = NodeIDStrategyFunc(hostnameOrdinalStrategy)
+	// MachineIDStrategy hashes the systemd machine ID, as NodeFromMachineID.
+	// It does not apply if there is no machine-id file.
+	MachineIDStrategy NodeIDStrategy = NodeIDStrategyFunc(machineIDStrategy)
+	// RandomStrategy picks a node ID at random. It always applies, so it
+	// belongs last in a chain.
+	RandomStrategy NodeIDStrategy = NodeIDStrategyFunc(randomStrategy)
+)
+
+// EnvStrategy reads the node ID from the environment variable envVar, as
+// NodeFromEnv. It does not apply if the variable is empty or unset.
+func EnvStrategy(envVar string) NodeIDStrategy {
+	return NodeIDStrategyFunc(func(context.Context) (int, error) {
+		return notApplicableOn(ErrNodeIDEnvUnset)(NodeFromEnv(envVar))
+	})
+}
+
+// NodeIDEnv is the environment variable NewWithHostAutoDiscovery reads the
+// node ID from first.
+const NodeIDEnv = "UID64_NODE_ID"
+
+// hostAutoDiscovery lists the strategies NewWithHostAutoDiscovery tries, with
+// the names it reports them by.
+var hostAutoDiscovery = []struct {
+	name     string
+	strategy NodeIDStrategy
+}{
+	{"env", EnvStrategy(NodeIDEnv)},
+	{"hostname-ordinal", HostnameOrdinalStrategy},
+	{"machine-id", MachineIDStrategy},
+	{"mac", MACStrategy},
+	{"random", RandomStrategy},
+}
+
+// NewWithHostAutoDiscovery returns a Generator configured by opts that derives
+// its node ID on first use from the first of these that applies: the
+// UID64_NODE_ID environment variable, the StatefulSet ordinal in the host
+// name, the systemd machine ID, the MAC addresses of the host, and finally a
+// random node ID. It suits code deployed to bare metal, VMs and Kubernetes
+// alike and is the recommended constructor when the node ID is not assigned
+// explicitly. A stats hook that implements NodeIDStrategyObserver is told
+// which strategy supplied the node ID. A strategy that applies but fails, such as a
+// malformed UID64_NODE_ID, makes NextID fail rather than falling through.
+func NewWithHostAutoDiscovery(opts ...GeneratorOption) (*Generator, error) {
+	var g *Generator
+	strategy := NodeIDStrategyFunc(func(ctx context.Context) (int, error) {
+		for _, s := range hostAutoDiscovery {
+			if err := ctx.Err(); err != nil {
+				return 0, err
+			}
+			nodeID, err := s.strategy.NodeID(ctx)
+			if errors.Is(err, ErrStrategyNotApplicable) {
+				continue
+			}
+			if o, ok := g.statsHook.(NodeIDStrategyObserver); ok && err == nil {
+				o.OnNodeIDStrategy(s.name, nodeID)
+			}
+			return nodeID, err
+		}
+		return 0, ErrStrategyNotApplicable
+	})
+	g, err := NewWithOptions(append([]GeneratorOption{WithNodeIDStrategy(strategy)}, opts...)...)
+	return g, err
+}
+
+func macStrategy(context.Context) (int, error) {
+	nodeID, ok, err := macNodeID(maxNodeID)
+	if err == nil && !ok {
+		err = notApplicable{errors.New("no network interface has a MAC address")}
+	}
+	return nodeID, err
+}
+
+func hostnameOrdinalStrategy(context.Context) (int, error) {
+	return notApplicableOn(ErrNoPodOrdinal)(NodeFromKubernetesStatefulSet())
+}
+
+func machineIDStrategy(context.Context) (int, error) {
+	return notApplicableOn(ErrNoMachineID)(NodeFromMachineID())
+}
+
+func randomStrategy(context.Context) (int, error) {
+	return rand.Intn(maxNodeID + 1), nil
+}
+
+// notApplicableOn returns a function that passes on the result of a NodeFrom
+// function, marking reason as ErrStrategyNotApplicable.
+func notApplicableOn(reason error) func(int, error) (int, error) {
+	return func(nodeID int, err error) (int, error) {
+		if errors.Is(err, reason) {
+			err = notApplicable{err}
+		}
+		return nodeID, err
+	}
+}
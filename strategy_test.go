@@ -0,0 +1,143 @@
+package uid64_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestChainedNodeIDStrategy(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+	fixed := func(nodeID int, err error) uid64.NodeIDStrategy {
+		return uid64.NodeIDStrategyFunc(func(context.Context) (int, error) {
+			calls++
+			return nodeID, err
+		})
+	}
+	notApplicable := fixed(0, uid64.ErrStrategyNotApplicable)
+
+	chain := uid64.ChainedNodeIDStrategy(notApplicable, fixed(7, nil), fixed(8, nil))
+	if got, err := chain.NodeID(ctx); err != nil || got != 7 {
+		t.Errorf("NodeID = %d, %v, want 7", got, err)
+	}
+	if calls != 2 {
+		t.Errorf("%d strategies called, want 2", calls)
+	}
+
+	errBroken := errors.New("broken")
+	chain = uid64.ChainedNodeIDStrategy(notApplicable, fixed(0, errBroken), fixed(8, nil))
+	if _, err := chain.NodeID(ctx); err != errBroken {
+		t.Errorf("err = %v, want %v", err, errBroken)
+	}
+	chain = uid64.ChainedNodeIDStrategy(notApplicable, notApplicable)
+	if _, err := chain.NodeID(ctx); err != uid64.ErrStrategyNotApplicable {
+		t.Errorf("err with no applicable strategy = %v, want ErrStrategyNotApplicable", err)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := uid64.ChainedNodeIDStrategy(fixed(7, nil)).NodeID(cancelled); err != context.Canceled {
+		t.Errorf("err with a cancelled context = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestBuiltinStrategies(t *testing.T) {
+	ctx := context.Background()
+	const env = "UID64_TEST_STRATEGY_NODE_ID"
+	os.Unsetenv(env)
+	defer uid64.SetHostname("web")()
+	defer uid64.SetMachineIDPaths(filepath.Join(t.TempDir(), "missing"))()
+
+	for name, s := range map[string]uid64.NodeIDStrategy{
+		"EnvStrategy":             uid64.EnvStrategy(env),
+		"HostnameOrdinalStrategy": uid64.HostnameOrdinalStrategy,
+		"MachineIDStrategy":       uid64.MachineIDStrategy,
+	} {
+		if _, err := s.NodeID(ctx); !errors.Is(err, uid64.ErrStrategyNotApplicable) {
+			t.Errorf("%s: err = %v, want ErrStrategyNotApplicable", name, err)
+		}
+	}
+	if _, err := uid64.HostnameOrdinalStrategy.NodeID(ctx); !errors.Is(err, uid64.ErrNoPodOrdinal) {
+		t.Errorf("HostnameOrdinalStrategy: err = %v, want it to wrap ErrNoPodOrdinal", err)
+	}
+
+	os.Setenv(env, "12")
+	defer os.Unsetenv(env)
+	uid64.SetHostname("web-3")
+	chain := uid64.ChainedNodeIDStrategy(uid64.MachineIDStrategy, uid64.HostnameOrdinalStrategy, uid64.EnvStrategy(env))
+	if got, err := chain.NodeID(ctx); err != nil || got != 3 {
+		t.Errorf("chained NodeID = %d, %v, want 3", got, err)
+	}
+	if got, err := uid64.EnvStrategy(env).NodeID(ctx); err != nil || got != 12 {
+		t.Errorf("EnvStrategy = %d, %v, want 12", got, err)
+	}
+	os.Setenv(env, "4096")
+	if _, err := uid64.EnvStrategy(env).NodeID(ctx); err != uid64.ErrOutOfBoundNodeID {
+		t.Errorf("EnvStrategy out of range: err = %v, want ErrOutOfBoundNodeID", err)
+	}
+
+	for i := 0; i < 100; i++ {
+		if got, err := uid64.RandomStrategy.NodeID(ctx); err != nil || got < 0 || got > 1023 {
+			t.Fatalf("RandomStrategy = %d, %v, want 0 to 1023", got, err)
+		}
+	}
+	if got, err := uid64.MACStrategy.NodeID(ctx); err != nil && !errors.Is(err, uid64.ErrStrategyNotApplicable) || got < 0 || got > 1023 {
+		t.Errorf("MACStrategy = %d, %v", got, err)
+	}
+}
+
+func TestNewWithHostAutoDiscovery(t *testing.T) {
+	defer os.Unsetenv(uid64.NodeIDEnv)
+	defer uid64.SetHostname("web")()
+	defer uid64.SetMachineIDPaths(filepath.Join(t.TempDir(), "missing"))()
+
+	for _, tc := range []struct {
+		env, hostname string
+		want          []string
+		nodeID        int
+	}{
+		{"12", "web-3", []string{"env"}, 12},
+		{"", "web-3", []string{"hostname-ordinal"}, 3},
+		{"", "web", []string{"mac", "random"}, -1},
+	} {
+		os.Setenv(uid64.NodeIDEnv, tc.env)
+		uid64.SetHostname(tc.hostname)
+		hook := &recordingHook{}
+		gen, err := uid64.NewWithHostAutoDiscovery(uid64.WithStatsHook(hook))
+		if err != nil {
+			t.Fatal(err)
+		}
+		nodeID := gen.NodeID()
+		gen.Close()
+		if nodeID < 0 || tc.nodeID >= 0 && nodeID != tc.nodeID {
+			t.Errorf("env %q, host name %q: node ID = %d, want %d", tc.env, tc.hostname, nodeID, tc.nodeID)
+		}
+		if len(hook.strategies) != 1 || !contains(tc.want, hook.strategies[0]) {
+			t.Errorf("env %q, host name %q: OnNodeIDStrategy saw %q, want one of %q", tc.env, tc.hostname, hook.strategies, tc.want)
+		}
+	}
+
+	os.Setenv(uid64.NodeIDEnv, "not a number")
+	gen, err := uid64.NewWithHostAutoDiscovery()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+	if _, err := gen.NextID(); err == nil {
+		t.Error("NextID succeeded with a malformed node ID variable")
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package uid64
+
+import "context"
+
+// Stream returns a channel with capacity buf fed with IDs from a goroutine
+// that calls NextID until ctx is done. The channel is closed when the
+// goroutine stops. If it stops because NextID failed, the error is available
+// from Err; cancelling ctx is not an error. Starting a Stream clears the
+// error of the one before.
+func (g *Generator) Stream(ctx context.Context, buf int) <-chan int64 {
+	g.lock.Lock()
+	g.streamErr = nil
+	g.streamID++
+	stream := g.streamID
+	g.lock.Unlock()
+
+	ids := make(chan int64, buf)
+	go func() {
+		defer close(ids)
+		for {
+			id, err := g.NextIDCtx(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					g.setStreamErr(stream, err)
+				}
+				return
+			}
+			select {
+			case ids <- id:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ids
+}
+
+// Err returns the error that stopped the most recent Stream, or nil.
+func (g *Generator) Err() error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	return g.streamErr
+}
+
+// setStreamErr records err as the error that stopped stream, unless a later
+// Stream has started since.
+func (g *Generator) setStreamErr(stream uint64, err error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if stream == g.streamID {
+		g.streamErr = err
+	}
+}
@@ -0,0 +1,66 @@
+package uid64_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestStream(t *testing.T) {
+	gen, err := uid64.NewWithNodeID(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ids := gen.Stream(ctx, 64)
+	last := int64(-1)
+	for i := 0; i < 100000; i++ {
+		id, ok := <-ids
+		if !ok {
+			t.Fatalf("stream closed after %d ids: %v", i, gen.Err())
+		}
+		if id <= last {
+			t.Fatalf("id %d not greater than previous %d", id, last)
+		}
+		last = id
+	}
+	cancel()
+	for range ids {
+	}
+	if err := gen.Err(); err != nil {
+		t.Errorf("Err after cancel = %v, want nil", err)
+	}
+}
+
+func TestStreamError(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	gen := newTestGenerator(t, clock.Now)
+	if _, err := gen.NextID(); err != nil {
+		t.Fatal(err)
+	}
+	clock.now--
+
+	for range gen.Stream(context.Background(), 0) {
+		t.Fatal("stream produced an id with the clock rolled back")
+	}
+	if err := gen.Err(); !errors.Is(err, uid64.ErrInvalidState) {
+		t.Errorf("Err = %v, want ErrInvalidState", err)
+	}
+
+	// A later Stream that is cancelled cleanly clears the error.
+	clock.now++
+	ctx, cancel := context.WithCancel(context.Background())
+	ids := gen.Stream(ctx, 0)
+	<-ids
+	cancel()
+	for range ids {
+	}
+	if err := gen.Err(); err != nil {
+		t.Errorf("Err after a cancelled Stream = %v, want nil", err)
+	}
+}
@@ -0,0 +1,36 @@
+package uid64
+
+import (
+	"errors"
+	"math"
+)
+
+var ErrInvalidTypeTag = errors.New("type tag must be 0 or 1")
+
+// WithTypeTag stores tag, which must be 0 or 1, in bit 63 of every ID, so two
+// kinds of ID, such as user and session IDs, can be told apart with TypeTagOf
+// without a lookup. Bit 63 is the sign bit, so IDs tagged 1 are negative:
+// they fail ValidateID, sort before untagged IDs as int64 values and need a
+// signed column in databases. SortIDs and the encodings order them after
+// untagged IDs, and every encoding decodes them back to the same negative
+// int64. EmbedVersion uses the same bit, so the two cannot be combined.
+func WithTypeTag(tag uint8) GeneratorOption {
+	return func(g *Generator) error {
+		if tag > 1 {
+			return ErrInvalidTypeTag
+		}
+		g.typeTag = int64(tag) * math.MinInt64
+		return nil
+	}
+}
+
+// TypeTagOf returns the type tag stored in bit 63 of id by WithTypeTag.
+func TypeTagOf(id int64) uint8 {
+	return uint8(uint64(id) >> 63)
+}
+
+// IsNegative reports whether id has the sign bit set, as IDs from a generator
+// with WithTypeTag(1) do.
+func IsNegative(id int64) bool {
+	return id < 0
+}
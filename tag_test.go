@@ -0,0 +1,53 @@
+package uid64_test
+
+import (
+	"testing"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestWithTypeTag(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	tagged, err := uid64.NewWithOptions(uid64.WithNodeID(1), uid64.WithClock(clock.Now), uid64.WithTypeTag(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tagged.Close()
+	plain, err := uid64.NewWithOptions(uid64.WithNodeID(2), uid64.WithClock(clock.Now), uid64.WithTypeTag(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer plain.Close()
+
+	a, err := tagged.NextInt64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := plain.NextInt64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uid64.TypeTagOf(a) != 1 || !uid64.IsNegative(a) {
+		t.Errorf("tagged ID %d: TypeTagOf = %d, IsNegative = %v, want 1, true", a, uid64.TypeTagOf(a), uid64.IsNegative(a))
+	}
+	if uid64.TypeTagOf(b) != 0 || uid64.IsNegative(b) {
+		t.Errorf("untagged ID %d: TypeTagOf = %d, IsNegative = %v, want 0, false", b, uid64.TypeTagOf(b), uid64.IsNegative(b))
+	}
+	if c := uid64.Decompose(a); c.Timestamp != 1000 || c.NodeID != 1 || c.Sequence != 0 {
+		t.Errorf("Decompose(tagged) = %+v, want timestamp 1000 node 1 sequence 0", c)
+	}
+
+	batch, err := tagged.NextIDBatch(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range batch {
+		if uid64.TypeTagOf(id) != 1 {
+			t.Errorf("batch ID %d is untagged", id)
+		}
+	}
+
+	if _, err := uid64.NewWithOptions(uid64.WithTypeTag(2)); err != uid64.ErrInvalidTypeTag {
+		t.Errorf("WithTypeTag(2): err = %v, want ErrInvalidTypeTag", err)
+	}
+}
@@ -1,15 +1,18 @@
 package uid64
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"hash/fnv"
 	"math"
 	"math/rand"
 	"net"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"golang.org/x/time/rate"
 )
 
 // Distributed Sequence Generator.
@@ -20,90 +23,544 @@ const (
 	epochBits    = 41
 	nodeIDBits   = 10
 	sequenceBits = 12
+	// Bits below the timestamp, shared by the node ID and sequence.
+	lowBits = nodeIDBits + sequenceBits
 )
 
 var (
 	maxNodeID   = int(math.Pow(2, nodeIDBits) - 1)
 	maxSequence = int(math.Pow(2, sequenceBits) - 1)
+	// Largest timestamp that fits in the epoch bits, roughly 69 years.
+	maxTimestamp = int64(math.Pow(2, epochBits) - 1)
+	// Largest batch NextIDBatch accepts unless configured otherwise.
+	defaultMaxBatchSize = 4096
 	// Custom Epoch (January 1, 2015 Midnight UTC = 2015-01-01T00:00:00Z)
 	customEpoch = int64(1420070400000)
+	// Earliest epoch WithEpoch accepts, which also rules out the zero time.
+	minEpoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	// Timestamp range WithEpoch requires to be left, ten years in milliseconds.
+	minEpochRange = int64(10 * 365 * 24 * time.Hour / time.Millisecond)
 )
 
 var (
-	ErrInvalidState     = errors.New("the system clock is invalid")
-	ErrOutOfBoundNodeID = fmt.Errorf("nodeID must be between 0 and %d", maxNodeID)
+	ErrInvalidState           = errors.New("the system clock is invalid")
+	ErrOutOfBoundNodeID       = fmt.Errorf("nodeID must be between 0 and %d", maxNodeID)
+	ErrOutOfBoundDatacenterID = errors.New("datacenter ID does not fit in the datacenter bits")
+	ErrOutOfBoundWorkerID     = errors.New("worker ID does not fit in the worker bits")
+	ErrBatchTooLarge          = errors.New("batch size exceeds the configured maximum")
+	ErrInvalidBatchSize       = errors.New("maximum batch size must be positive")
+	ErrExcessiveClockDrift    = errors.New("the system clock went backwards beyond the drift tolerance")
+	ErrInvalidDriftTolerance  = errors.New("clock drift tolerance must not be negative")
+	ErrEpochInFuture          = errors.New("epoch is in the future")
+	ErrEpochTooEarly          = errors.New("epoch is before 2000-01-01")
+	ErrEpochRangeInsufficient = errors.New("epoch leaves less than ten years in the timestamp field")
+	ErrInvalidInitialSequence = errors.New("initial sequence does not fit in the sequence bits")
+
+	errSequenceExhausted = errors.New("sequence exhausted")
 )
 
 type Generator struct {
-	nodeID        int
-	lock          sync.Mutex
-	lastTimestamp int64
-	sequence      int64
+	// state packs the last timestamp and sequence so NextID can advance both
+	// with a single compare-and-swap. It and the counters below are accessed
+	// atomically and kept first in the struct for 64-bit alignment on 32-bit
+	// platforms.
+	state uint64
+	// Counters reported by Stats, updated atomically.
+	totalGenerated      int64
+	sequenceExhaustions int64
+	clockRollbacks      int64
+	// firstTimestamp is one more than the timestamp of the first ID issued
+	// since construction, or zero if there is none yet. NextIDAt stamps IDs
+	// only before it.
+	firstTimestamp int64
+
+	// nodeIDResolved is set atomically once nodeID holds its final value.
+	nodeIDResolved uint32
+	nodeID         int
+	strategy       NodeIDStrategy
+	epoch          int64
+	clock          ClockFunc
+	waitStrategy   WaitStrategy
+	statsHook      StatsHook
+	layout         layout
+	datacenterBits uint
+	workerBits     uint
+	maxBatchSize   int
+	// typeTag is set by WithTypeTag and copied into the layout, since bit
+	// layout options may replace the layout after it is applied.
+	typeTag int64
+	// initialSequence is the sequence of the first ID after construction or
+	// Reset.
+	initialSequence int64
+	// driftTolerance is how many milliseconds the clock may go backwards
+	// before NextID fails rather than waits.
+	driftTolerance int64
+	// closed is set atomically by Close.
+	closed uint32
+	// expiryWarned is set atomically once expiryHook has fired.
+	expiryWarned    uint32
+	expiryThreshold time.Duration
+	expiryHook      func(remaining time.Duration)
+	// limiter enforces WithFloodProtection's limit of floodLimit IDs per
+	// second. It is nil if there is no limit.
+	limiter    *rate.Limiter
+	floodLimit int
+	// circuit is the configuration of breaker, which is nil unless
+	// WithCircuitBreaker is used.
+	circuit circuitConfig
+	breaker *circuitBreaker
+	// monotonic and divergenceThreshold are set by WithMonotonicClock and
+	// WithClockDivergenceThreshold; the clock is built from them once the
+	// epoch and stats hook are known.
+	monotonic           bool
+	divergenceThreshold time.Duration
+	// expvarName is the name set by WithExpvar, if any.
+	expvarName string
+	// registerMetrics holds the registrations of WithPrometheusMetrics, run
+	// by publish. Each returns a function that undoes it.
+	registerMetrics []func() (func(), error)
+	// unregistered is set by DisableRegistry. claim is the registry entry
+	// held by g, if any, and is guarded by the registry.
+	unregistered bool
+	claim        *claim
+	lock         sync.Mutex
+	// streamErr is the error that stopped the Stream numbered streamID, the
+	// most recent one. Both are guarded by lock.
+	streamErr error
+	streamID  uint64
+	// importTimestamp is one more than the timestamp of the last ID from
+	// NextIDAt, or zero if there is none, and importSequence the sequence
+	// its next ID takes. Both are guarded by lock.
+	importTimestamp int64
+	importSequence  int64
 }
 
+// New returns a Generator that derives its node ID from the host on first
+// use. If another generator in the process already holds that node ID, NextID
+// fails with ErrNodeIDInUse. Every generator from New derives the same node
+// ID, so a second one fails until the first is closed, and so does one whose
+// node ID falls within the range of a GeneratorPool, which claims node IDs
+// from 0 unless given WithNodeID. Share one generator, Close those no longer
+// needed, or give each its own node ID; NewWithOptions with DisableRegistry
+// skips the check for callers that coordinate node IDs themselves.
 func New() *Generator {
-	return &Generator{
-		lastTimestamp: -1,
-	}
+	// NewWithOptions cannot fail without options.
+	g, _ := NewWithOptions()
+	return g
 }
 
 func NewWithNodeID(nodeID int) (*Generator, error) {
-	if nodeID < 0 || nodeID > maxNodeID {
+	return NewWithOptions(WithNodeID(nodeID))
+}
+
+// NewWithDatacenterWorker returns a Generator whose node ID combines
+// datacenterID and workerID. Unless opts include WithDatacenterLayout, the node
+// ID is split as in Twitter's Snowflake: 5 datacenter bits and 5 worker bits.
+func NewWithDatacenterWorker(datacenterID, workerID int, opts ...GeneratorOption) (*Generator, error) {
+	g, err := newGenerator(append([]GeneratorOption{WithDatacenterLayout(5, 5)}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+	if datacenterID < 0 || datacenterID > g.layout.maxDatacenterID() {
+		return nil, ErrOutOfBoundDatacenterID
+	}
+	if workerID < 0 || workerID > g.layout.maxWorkerID() {
+		return nil, ErrOutOfBoundWorkerID
+	}
+	g.nodeID = datacenterID<<g.layout.workerBits | workerID
+	g.nodeIDResolved = 1
+	if err := g.register(g.nodeID); err != nil {
+		return nil, err
+	}
+	if err := g.publish(); err != nil {
+		g.Close()
+		return nil, err
+	}
+	return g, nil
+}
+
+// NewWithOptions returns a Generator configured by opts. Unless opts include
+// DisableRegistry, a node ID set with WithNodeID is claimed in a process-wide
+// registry, and NewWithOptions fails with ErrNodeIDInUse if another generator
+// holds it; call Close to release it. A node ID derived by a NodeIDStrategy is
+// claimed when it is resolved.
+func NewWithOptions(opts ...GeneratorOption) (*Generator, error) {
+	g, err := newGenerator(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if g.nodeIDResolved == 1 {
+		if err := g.register(g.nodeID); err != nil {
+			return nil, err
+		}
+	}
+	if err := g.publish(); err != nil {
+		g.Close()
+		return nil, err
+	}
+	return g, nil
+}
+
+// publish makes a successfully constructed g visible to monitoring: it
+// registers the collectors of WithPrometheusMetrics and publishes the map of
+// WithExpvar. If that fails it unregisters the collectors again; expvar offers
+// no way to withdraw a name.
+func (g *Generator) publish() error {
+	var undo []func()
+	rollback := func() {
+		for _, u := range undo {
+			u()
+		}
+	}
+	for _, register := range g.registerMetrics {
+		u, err := register()
+		if err != nil {
+			rollback()
+			return err
+		}
+		undo = append(undo, u)
+	}
+	if err := g.publishExpvar(); err != nil {
+		rollback()
+		return err
+	}
+	return nil
+}
+
+// newGenerator is NewWithOptions without claiming the node ID.
+func newGenerator(opts ...GeneratorOption) (*Generator, error) {
+	g := &Generator{
+		epoch:        customEpoch,
+		layout:       defaultLayout,
+		maxBatchSize: defaultMaxBatchSize,
+	}
+	for _, opt := range opts {
+		if err := opt(g); err != nil {
+			return nil, err
+		}
+	}
+	if g.datacenterBits+g.workerBits > 0 {
+		if g.datacenterBits+g.workerBits != g.layout.NodeIDBits {
+			return nil, ErrInvalidDatacenterLayout
+		}
+		g.layout.datacenterBits = g.datacenterBits
+		g.layout.workerBits = g.workerBits
+	}
+	g.layout.tag = g.typeTag
+	if g.nodeIDResolved == 1 && (g.nodeID < 0 || g.nodeID > g.layout.maxNodeID) {
 		return nil, ErrOutOfBoundNodeID
 	}
+	if g.initialSequence < 0 || g.initialSequence > g.layout.maxSequence {
+		return nil, ErrInvalidInitialSequence
+	}
+	breaker, err := g.circuit.newBreaker()
+	if err != nil {
+		return nil, err
+	}
+	g.breaker = breaker
+	if g.strategy == nil {
+		g.strategy = NodeIDStrategyFunc(func(context.Context) (int, error) {
+			return createNodeID(g.layout.maxNodeID)
+		})
+	}
+	if g.waitStrategy == nil {
+		g.waitStrategy = SpinWaitStrategy
+	}
+	if g.statsHook == nil {
+		g.statsHook = NoopStatsHook{}
+	}
+	if g.clock == nil && g.monotonic {
+		if g.divergenceThreshold == 0 {
+			g.divergenceThreshold = defaultDivergenceThreshold
+		}
+		g.clock = monotonicClock(g.epoch, g.divergenceThreshold, g.statsHook.OnClockDivergence)
+	}
+	if g.clock == nil {
+		g.clock = systemClock(g.epoch)
+	}
+	return g, nil
+}
+
+// NextID is safe for concurrent use. It does not take a lock; concurrent
+// callers race on a compare-and-swap of the generator state and retry on loss.
+func (g *Generator) NextID() (ID, error) {
+	id, err := g.nextID(context.Background(), true)
+	return ID(id), err
+}
+
+// NextInt64 is like NextID but returns the ID as an int64, for callers that
+// store or pass IDs as plain integers.
+func (g *Generator) NextInt64() (int64, error) {
+	return g.nextID(context.Background(), true)
+}
+
+// NextIDCtx is like NextInt64 but gives up with ctx.Err() if ctx is done while
+// waiting for the next millisecond after the sequence is exhausted.
+func (g *Generator) NextIDCtx(ctx context.Context) (int64, error) {
+	return g.nextID(ctx, true)
+}
+
+// TryNextID is like NextInt64 but never waits: it reports false instead of
+// blocking when the sequence for the current millisecond is exhausted, or if
+// NextID would have failed.
+func (g *Generator) TryNextID() (int64, bool) {
+	id, err := g.nextID(context.Background(), false)
+	return id, err == nil
+}
+
+func (g *Generator) nextID(ctx context.Context, block bool) (int64, error) {
+	timestamp, nodeID, sequence, err := g.next(ctx, block)
+	if err != nil {
+		return 0, err
+	}
+	// The check is left out of next, since WideGenerator carries timestamps
+	// past the limit of int64 IDs.
+	if timestamp > g.layout.MaxTimestamp() {
+		return 0, ErrGeneratorExpired
+	}
+	g.checkExpiry(timestamp)
+	id := g.layout.compose(timestamp, nodeID, sequence)
+	g.statsHook.OnGenerate(id)
+	return id, nil
+}
+
+// next reserves the fields of one ID without composing them, so that callers
+// other than nextID may lay them out differently.
+func (g *Generator) next(ctx context.Context, block bool) (timestamp int64, nodeID int, sequence int64, err error) {
+	if atomic.LoadUint32(&g.closed) == 1 {
+		return 0, 0, 0, ErrGeneratorClosed
+	}
+	nodeID, err = g.resolveNodeID(ctx)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if err := g.breaker.allow(); err != nil {
+		return 0, 0, 0, err
+	}
+	if err := g.throttle(ctx, block, 1); err != nil {
+		return 0, 0, 0, err
+	}
+
+	for {
+		current := atomic.LoadUint64(&g.state)
+		lastTimestamp, lastSequence := g.layout.unpackState(current)
+		currentTimestamp := g.clock()
+
+		switch {
+		case currentTimestamp < lastTimestamp:
+			g.countClockRollback(lastTimestamp - currentTimestamp)
+			if !block {
+				return 0, 0, 0, &ClockRollbackError{Observed: currentTimestamp, Last: lastTimestamp}
+			}
+			if err := g.waitForClockDrift(ctx, currentTimestamp, lastTimestamp); err != nil {
+				return 0, 0, 0, err
+			}
+			continue
+		case currentTimestamp == lastTimestamp:
+			sequence = (lastSequence + 1) & g.layout.maxSequence
+			if sequence == 0 {
+				// Sequence Exhausted, wait till next millisecond and retry.
+				g.countSequenceExhaustion()
+				if !block {
+					return 0, 0, 0, errSequenceExhausted
+				}
+				if err := g.blockWaitToNextMillisecond(ctx, lastTimestamp); err != nil {
+					return 0, 0, 0, &SequenceExhaustedError{Timestamp: lastTimestamp, Err: err}
+				}
+				continue
+			}
+		case current == 0:
+			// First ID since construction or Reset.
+			sequence = g.initialSequence
+		default:
+			sequence = 0
+		}
+
+		if !atomic.CompareAndSwapUint64(&g.state, current, g.layout.packState(currentTimestamp, sequence)) {
+			continue
+		}
+		if current == 0 {
+			atomic.CompareAndSwapInt64(&g.firstTimestamp, 0, currentTimestamp+1)
+		}
+		atomic.AddInt64(&g.totalGenerated, 1)
+		g.breaker.succeed()
+		return currentTimestamp, nodeID, sequence, nil
+	}
+}
+
+// NextIDBatch returns n IDs in ascending order. Sequence numbers are reserved
+// a millisecond at a time, so it costs one compare-and-swap per millisecond
+// spanned rather than one per ID.
+func (g *Generator) NextIDBatch(n int) ([]int64, error) {
+	if n > g.maxBatchSize {
+		return nil, ErrBatchTooLarge
+	}
+	if n <= 0 {
+		return nil, nil
+	}
+	if atomic.LoadUint32(&g.closed) == 1 {
+		return nil, ErrGeneratorClosed
+	}
+	nodeID, err := g.resolveNodeID(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	if err := g.breaker.allow(); err != nil {
+		return nil, err
+	}
+	if err := g.throttle(context.Background(), true, n); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, n)
+	for len(ids) < n {
+		current := atomic.LoadUint64(&g.state)
+		lastTimestamp, sequence := g.layout.unpackState(current)
+		currentTimestamp := g.clock()
+		if currentTimestamp > g.layout.MaxTimestamp() {
+			return nil, ErrGeneratorExpired
+		}
+
+		var first int64
+		switch {
+		case currentTimestamp < lastTimestamp:
+			g.countClockRollback(lastTimestamp - currentTimestamp)
+			if err := g.waitForClockDrift(context.Background(), currentTimestamp, lastTimestamp); err != nil {
+				return nil, err
+			}
+			continue
+		case currentTimestamp == lastTimestamp:
+			first = sequence + 1
+			if first > g.layout.maxSequence {
+				g.countSequenceExhaustion()
+				if err := g.blockWaitToNextMillisecond(context.Background(), lastTimestamp); err != nil {
+					return nil, &SequenceExhaustedError{Timestamp: lastTimestamp, Err: err}
+				}
+				continue
+			}
+		case current == 0:
+			first = g.initialSequence
+		}
+
+		last := first + int64(n-len(ids)) - 1
+		if last > g.layout.maxSequence {
+			last = g.layout.maxSequence
+		}
+		if !atomic.CompareAndSwapUint64(&g.state, current, g.layout.packState(currentTimestamp, last)) {
+			continue
+		}
+		if current == 0 {
+			atomic.CompareAndSwapInt64(&g.firstTimestamp, 0, currentTimestamp+1)
+		}
+		for seq := first; seq <= last; seq++ {
+			ids = append(ids, g.layout.compose(currentTimestamp, nodeID, seq))
+		}
+		atomic.AddInt64(&g.totalGenerated, last-first+1)
+		g.breaker.succeed()
+		g.checkExpiry(currentTimestamp)
+		for _, id := range ids[len(ids)-int(last-first+1):] {
+			g.statsHook.OnGenerate(id)
+		}
+	}
+	return ids, nil
+}
 
-	return &Generator{
-		lastTimestamp: -1,
-		nodeID:        nodeID,
-	}, nil
+// NodeID returns the node ID embedded in g's IDs, deriving it first if NextID
+// has not done so yet. It returns -1 if the node ID cannot be derived.
+func (g *Generator) NodeID() int {
+	nodeID, err := g.resolveNodeID(context.Background())
+	if err != nil {
+		return -1
+	}
+	return nodeID
 }
 
-func (g *Generator) NextID() (int64, error) {
+// WarmUp derives g's node ID if it has not been already, then generates and
+// discards n IDs. WarmUp(0) therefore resolves the node ID, reporting any
+// error NextID would, without touching the sequence.
+func (g *Generator) WarmUp(n int) error {
+	if _, err := g.resolveNodeID(context.Background()); err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		if _, err := g.NextID(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LastTimestamp returns the timestamp of the last ID g produced, in
+// milliseconds since its epoch, or -1 if it has produced none.
+func (g *Generator) LastTimestamp() int64 {
+	lastTimestamp, _ := g.layout.unpackState(atomic.LoadUint64(&g.state))
+	return lastTimestamp
+}
+
+func (g *Generator) resolveNodeID(ctx context.Context) (int, error) {
+	if atomic.LoadUint32(&g.nodeIDResolved) == 1 {
+		return g.nodeID, nil
+	}
+
 	g.lock.Lock()
 	defer g.lock.Unlock()
-	if g.nodeID == 0 {
-		nid, err := createNodeID()
+	if g.nodeIDResolved == 0 {
+		nid, err := g.strategy.NodeID(ctx)
 		if err != nil {
 			return 0, err
 		}
+		if nid < 0 || nid > g.layout.maxNodeID {
+			return 0, ErrOutOfBoundNodeID
+		}
+		if err := g.register(nid); err != nil {
+			return 0, err
+		}
 		g.nodeID = nid
+		atomic.StoreUint32(&g.nodeIDResolved, 1)
 	}
+	return g.nodeID, nil
+}
 
-	currentTimestamp := timestamp()
+// waitForClockDrift handles the clock reading currentTimestamp after an ID was
+// issued at lastTimestamp. Within the configured drift tolerance it waits for
+// the clock to catch up; otherwise it reports the rollback.
+func (g *Generator) waitForClockDrift(ctx context.Context, currentTimestamp, lastTimestamp int64) error {
+	drift := lastTimestamp - currentTimestamp
+	if g.driftTolerance == 0 {
+		return &ClockRollbackError{Observed: currentTimestamp, Last: lastTimestamp}
+	}
+	if drift > g.driftTolerance {
+		return fmt.Errorf("%w: clock is %v behind", ErrExcessiveClockDrift, time.Duration(drift)*time.Millisecond)
+	}
+	return g.blockWaitToNextMillisecond(ctx, lastTimestamp-1)
+}
 
-	switch {
-	case currentTimestamp < g.lastTimestamp:
-		return 0, ErrInvalidState
-	case currentTimestamp == g.lastTimestamp:
-		g.sequence = (g.sequence + 1) & int64(maxSequence)
-		if g.sequence == 0 {
-			// Sequence Exhausted, wait till next millisecond.
-			currentTimestamp = g.blockWaitToNextMillisecond(currentTimestamp)
+// blockWaitToNextMillisecond waits with g's WaitStrategy until the clock moves
+// past lastTimestamp or ctx is done. It holds no lock so other callers are free
+// to observe the new millisecond first.
+func (g *Generator) blockWaitToNextMillisecond(ctx context.Context, lastTimestamp int64) error {
+	for g.clock() <= lastTimestamp {
+		if err := g.waitStrategy.Wait(ctx); err != nil {
+			return err
 		}
-	default:
-		g.sequence = 0
 	}
-
-	g.lastTimestamp = currentTimestamp
-	id := currentTimestamp << (nodeIDBits + sequenceBits)
-	id |= int64(g.nodeID) << sequenceBits
-	id |= g.sequence
-	return id, nil
-
+	return nil
 }
 
-func (g *Generator) blockWaitToNextMillisecond(currentTimestamp int64) int64 {
-	for g.lastTimestamp == currentTimestamp {
-		currentTimestamp = timestamp()
+func createNodeID(maxNodeID int) (int, error) {
+	nodeID, ok, err := macNodeID(maxNodeID)
+	if err != nil || ok {
+		return nodeID, err
 	}
-	return currentTimestamp
+	return rand.Intn(maxNodeID + 1), nil
 }
 
-func createNodeID() (int, error) {
-	var nodeID int
+// macNodeID hashes the MAC addresses of the host's network interfaces,
+// reporting false if there are none.
+func macNodeID(maxNodeID int) (int, bool, error) {
 	ifaces, err := net.Interfaces()
 	if err != nil {
-		return nodeID, err
+		return 0, false, err
 	}
 	var sb strings.Builder
 	for i := 0; i < len(ifaces); i++ {
@@ -113,13 +570,13 @@ func createNodeID() (int, error) {
 		}
 	}
 	if sb.Len() == 0 {
-		return rand.Int(), nil
+		return 0, false, nil
 	}
-	h := fnv.New32a()
-	h.Write([]byte(sb.String()))
-	return int(h.Sum32()) & maxNodeID, nil
+	return hashNodeID([]byte(sb.String()), maxNodeID), true, nil
 }
 
-func timestamp() int64 {
-	return (time.Now().UnixNano() / int64(time.Millisecond)) - customEpoch
+func systemClock(epoch int64) ClockFunc {
+	return func() int64 {
+		return unixMilli(time.Now()) - epoch
+	}
 }
@@ -1,15 +1,431 @@
 package uid64_test
 
 import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/Ahmed-Sermani/uid64"
 )
 
-var g = uid64.New()
+// g stays out of the node ID registry. As one from New, it would claim its
+// derived node ID on first use, and tests that claim that node ID explicitly,
+// or a pool covering it, would then fail with ErrNodeIDInUse.
+var g, _ = uid64.NewWithOptions(uid64.DisableRegistry())
 
 func Benchmark(b *testing.B) {
 	for n := 0; n < b.N; n++ {
 		g.NextID()
 	}
 }
+
+// Run with -cpu 8 to compare the lock-free NextID against callers serialized
+// on a mutex, which is how NextID used to behave.
+func BenchmarkParallel(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			g.NextID()
+		}
+	})
+}
+
+func BenchmarkParallelMutex(b *testing.B) {
+	var mu sync.Mutex
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			mu.Lock()
+			g.NextID()
+			mu.Unlock()
+		}
+	})
+}
+
+func TestNextIDConcurrentUnique(t *testing.T) {
+	const goroutines = 8
+	total := 10000000
+	if testing.Short() {
+		total = 100000
+	}
+	gen, err := uid64.NewWithNodeID(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+
+	results := make([][]int64, goroutines)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ids := make([]int64, total/goroutines)
+			for j := range ids {
+				id, err := gen.NextID()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				ids[j] = int64(id)
+			}
+			results[i] = ids
+		}(i)
+	}
+	wg.Wait()
+
+	all := make([]int64, 0, total)
+	for _, ids := range results {
+		all = append(all, ids...)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+	for i := 1; i < len(all); i++ {
+		if all[i] == all[i-1] {
+			t.Fatalf("duplicate id %d", all[i])
+		}
+	}
+}
+
+type fakeClock struct {
+	now int64
+}
+
+func (c *fakeClock) Now() int64 {
+	return c.now
+}
+
+func newTestGenerator(t testing.TB, clock uid64.ClockFunc) *uid64.Generator {
+	t.Helper()
+	gen, err := uid64.NewWithOptions(uid64.WithNodeID(1), uid64.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { gen.Close() })
+	return gen
+}
+
+func TestNextIDFrozenClock(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	gen := newTestGenerator(t, clock.Now)
+
+	var last uid64.ID = -1
+	for seq := int64(0); seq < 4096; seq++ {
+		id, err := gen.NextID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id <= last {
+			t.Fatalf("id %d not greater than previous %d", id, last)
+		}
+		c := uid64.Decompose(int64(id))
+		if c.Timestamp != 1000 || c.Sequence != seq {
+			t.Fatalf("Decompose = %+v, want timestamp 1000 sequence %d", c, seq)
+		}
+		last = id
+	}
+}
+
+func TestNextIDSequenceExhausted(t *testing.T) {
+	// The clock stays frozen until the sequence wraps, then moves on by
+	// exactly one millisecond.
+	var reads int
+	clock := func() int64 {
+		reads++
+		if reads > 4097 {
+			return 1001
+		}
+		return 1000
+	}
+	gen := newTestGenerator(t, clock)
+
+	for i := 0; i < 4096; i++ {
+		if _, err := gen.NextID(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	id, err := gen.NextID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := uid64.Decompose(int64(id)); c.Timestamp != 1001 || c.Sequence != 0 {
+		t.Errorf("Decompose = %+v, want timestamp 1001 sequence 0", c)
+	}
+}
+
+func TestNextIDClockAdvance(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	gen := newTestGenerator(t, clock.Now)
+
+	first, err := gen.NextID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gen.NextID(); err != nil {
+		t.Fatal(err)
+	}
+	clock.now++
+	next, err := gen.NextID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next <= first {
+		t.Errorf("id %d not greater than %d", next, first)
+	}
+	if c := uid64.Decompose(int64(next)); c.Timestamp != 1001 || c.Sequence != 0 {
+		t.Errorf("Decompose = %+v, want timestamp 1001 sequence 0", c)
+	}
+}
+
+func TestNextIDClockRollback(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	gen := newTestGenerator(t, clock.Now)
+
+	if _, err := gen.NextID(); err != nil {
+		t.Fatal(err)
+	}
+	clock.now--
+	_, err := gen.NextID()
+	if !errors.Is(err, uid64.ErrInvalidState) {
+		t.Errorf("err = %v, want ErrInvalidState", err)
+	}
+	var rollback *uid64.ClockRollbackError
+	if !errors.As(err, &rollback) {
+		t.Fatalf("err = %v, want a *ClockRollbackError", err)
+	}
+	if rollback.Observed != 999 || rollback.Last != 1000 || rollback.Drift() != -time.Millisecond {
+		t.Errorf("ClockRollbackError = %+v, drift %v, want observed 999, last 1000, drift -1ms", rollback, rollback.Drift())
+	}
+	if got, want := err.Error(), "clock rolled back: observed 999 < last 1000 (drift: -1ms)"; got != want {
+		t.Errorf("Error = %q, want %q", got, want)
+	}
+}
+
+func TestNextIDBatch(t *testing.T) {
+	// Advance the clock on every read so the batch has to span milliseconds.
+	var now int64 = 1000
+	gen := newTestGenerator(t, func() int64 {
+		now++
+		return now / 2
+	})
+	if _, err := gen.NextID(); err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := gen.NextIDBatch(4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 4096 {
+		t.Fatalf("len = %d, want 4096", len(ids))
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("ids[%d] = %d not greater than ids[%d] = %d", i, ids[i], i-1, ids[i-1])
+		}
+	}
+	next, err := gen.NextID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int64(next) <= ids[len(ids)-1] {
+		t.Errorf("NextID = %d not greater than last batch id %d", next, ids[len(ids)-1])
+	}
+}
+
+func TestNextIDBatchTooLarge(t *testing.T) {
+	gen, err := uid64.NewWithOptions(uid64.WithNodeID(1), uid64.WithMaxBatchSize(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+	if _, err := gen.NextIDBatch(11); err != uid64.ErrBatchTooLarge {
+		t.Errorf("err = %v, want ErrBatchTooLarge", err)
+	}
+	if _, err := uid64.NewWithOptions(uid64.WithMaxBatchSize(0)); err != uid64.ErrInvalidBatchSize {
+		t.Errorf("err = %v, want ErrInvalidBatchSize", err)
+	}
+}
+
+func BenchmarkNextIDBatch(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		g.NextIDBatch(1000)
+	}
+}
+
+func BenchmarkNextIDSerial1000(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		for i := 0; i < 1000; i++ {
+			g.NextID()
+		}
+	}
+}
+
+func TestNextIDCtxCancelled(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	gen := newTestGenerator(t, clock.Now)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	for i := 0; i < 4096; i++ {
+		if _, err := gen.NextIDCtx(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cancel()
+	// The clock never advances, so only cancellation can end the wait.
+	_, err := gen.NextIDCtx(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	var exhausted *uid64.SequenceExhaustedError
+	if !errors.As(err, &exhausted) || exhausted.Timestamp != 1000 {
+		t.Errorf("err = %#v, want a *SequenceExhaustedError at timestamp 1000", err)
+	}
+}
+
+func TestTryNextID(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	gen := newTestGenerator(t, clock.Now)
+
+	for i := 0; i < 4096; i++ {
+		if _, ok := gen.TryNextID(); !ok {
+			t.Fatalf("TryNextID failed after %d ids", i)
+		}
+	}
+	if id, ok := gen.TryNextID(); ok || id != 0 {
+		t.Errorf("TryNextID = (%d, %v), want (0, false)", id, ok)
+	}
+	clock.now++
+	id, ok := gen.TryNextID()
+	if !ok {
+		t.Fatal("TryNextID failed after the clock advanced")
+	}
+	if c := uid64.Decompose(id); c.Timestamp != 1001 || c.Sequence != 0 {
+		t.Errorf("Decompose = %+v, want timestamp 1001 sequence 0", c)
+	}
+}
+
+func TestIntrospection(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	gen, err := uid64.NewWithOptions(
+		uid64.WithClock(clock.Now),
+		uid64.WithNodeIDStrategy(uid64.NodeIDStrategyFunc(func(context.Context) (int, error) { return 12, nil })),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+	if got := gen.LastTimestamp(); got != -1 {
+		t.Errorf("LastTimestamp before NextID = %d, want -1", got)
+	}
+	if got := gen.NodeID(); got != 12 {
+		t.Errorf("NodeID = %d, want 12", got)
+	}
+	if _, err := gen.NextID(); err != nil {
+		t.Fatal(err)
+	}
+	if got := gen.LastTimestamp(); got != 1000 {
+		t.Errorf("LastTimestamp = %d, want 1000", got)
+	}
+
+	gen, err = uid64.NewWithOptions(uid64.WithNodeIDStrategy(uid64.NodeIDStrategyFunc(func(context.Context) (int, error) {
+		return 0, errors.New("unavailable")
+	})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := gen.NodeID(); got != -1 {
+		t.Errorf("NodeID with a failing strategy = %d, want -1", got)
+	}
+}
+
+func TestWarmUp(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	calls := 0
+	gen, err := uid64.NewWithOptions(
+		uid64.WithClock(clock.Now),
+		uid64.WithNodeIDStrategy(uid64.NodeIDStrategyFunc(func(context.Context) (int, error) { calls++; return 12, nil })),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+
+	if err := gen.WarmUp(0); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("node ID strategy called %d times, want 1", calls)
+	}
+	if got := gen.LastTimestamp(); got != -1 {
+		t.Errorf("LastTimestamp after WarmUp(0) = %d, want -1", got)
+	}
+
+	if err := gen.WarmUp(3); err != nil {
+		t.Fatal(err)
+	}
+	id, err := gen.NextID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := uid64.SequenceOf(int64(id)); got != 3 {
+		t.Errorf("sequence after WarmUp(3) = %d, want 3", got)
+	}
+
+	errStrategy := errors.New("unavailable")
+	gen, err = uid64.NewWithOptions(uid64.WithNodeIDStrategy(uid64.NodeIDStrategyFunc(func(context.Context) (int, error) {
+		return 0, errStrategy
+	})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gen.WarmUp(0); !errors.Is(err, errStrategy) {
+		t.Errorf("WarmUp with a failing strategy: err = %v, want %v", err, errStrategy)
+	}
+}
+
+func TestNextIDClockDriftTolerance(t *testing.T) {
+	// Once rolled back, the clock ticks forward on every read.
+	var now int64 = 1000
+	ticking := false
+	clock := func() int64 {
+		if ticking {
+			now++
+		}
+		return now
+	}
+	gen, err := uid64.NewWithOptions(
+		uid64.WithNodeID(1),
+		uid64.WithClock(clock),
+		uid64.WithClockDriftTolerance(5*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+	first, err := gen.NextID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now, ticking = 997, true
+	id, err := gen.NextID()
+	if err != nil {
+		t.Fatalf("NextID within tolerance: %v", err)
+	}
+	if id <= first {
+		t.Errorf("id %d not greater than %d", id, first)
+	}
+
+	ticking = false
+	last := gen.LastTimestamp()
+	now = last - 10
+	_, err = gen.NextID()
+	if !errors.Is(err, uid64.ErrExcessiveClockDrift) {
+		t.Fatalf("err = %v, want ErrExcessiveClockDrift", err)
+	}
+	if !strings.Contains(err.Error(), "10ms") {
+		t.Errorf("error %q does not report the 10ms drift", err)
+	}
+}
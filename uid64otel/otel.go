@@ -0,0 +1,94 @@
+// Package uid64otel traces ID generation with OpenTelemetry. It is a module of
+// its own so that only programs requiring it depend on OpenTelemetry.
+package uid64otel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+// Span attribute keys set on successful calls.
+const (
+	AttributeID          = attribute.Key("uid64.id")
+	AttributeNodeID      = attribute.Key("uid64.node_id")
+	AttributeSequence    = attribute.Key("uid64.sequence")
+	AttributeTimestampMS = attribute.Key("uid64.timestamp_ms")
+	AttributeBatchSize   = attribute.Key("uid64.batch_size")
+)
+
+type tracedGenerator struct {
+	g      uid64.IDGenerator
+	tracer trace.Tracer
+}
+
+// WrapGenerator returns an IDGenerator that records a span with tracer around
+// each call to g. Spans from NextID carry the ID, node ID, sequence and Unix
+// timestamp in milliseconds; spans from NextIDBatch carry the node ID and
+// batch size. Failed calls record the error on the span.
+func WrapGenerator(g uid64.IDGenerator, tracer trace.Tracer) uid64.IDGenerator {
+	return &tracedGenerator{g: g, tracer: tracer}
+}
+
+// WithTracer returns a function that wraps generators as WrapGenerator does,
+// for composing with other decorators.
+func WithTracer(tracer trace.Tracer) func(uid64.IDGenerator) uid64.IDGenerator {
+	return func(g uid64.IDGenerator) uid64.IDGenerator {
+		return WrapGenerator(g, tracer)
+	}
+}
+
+func (t *tracedGenerator) NextID() (uid64.ID, error) {
+	_, span := t.tracer.Start(context.Background(), "uid64.NextID")
+	defer span.End()
+	id, err := t.g.NextID()
+	if err != nil {
+		recordError(span, err)
+		return 0, err
+	}
+	c := t.decompose(int64(id))
+	span.SetAttributes(
+		AttributeID.Int64(int64(id)),
+		AttributeNodeID.Int(c.NodeID),
+		AttributeSequence.Int64(c.Sequence),
+		AttributeTimestampMS.Int64(c.Time.UnixNano()/1e6),
+	)
+	return id, nil
+}
+
+func (t *tracedGenerator) NextIDBatch(n int) ([]int64, error) {
+	_, span := t.tracer.Start(context.Background(), "uid64.NextIDBatch")
+	defer span.End()
+	span.SetAttributes(AttributeBatchSize.Int(n))
+	ids, err := t.g.NextIDBatch(n)
+	if err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+	span.SetAttributes(AttributeNodeID.Int(t.g.NodeID()))
+	return ids, nil
+}
+
+func (t *tracedGenerator) NodeID() int {
+	return t.g.NodeID()
+}
+
+// decompose uses the wrapped generator's own layout and epoch when it has
+// them, as *uid64.Generator does.
+func (t *tracedGenerator) decompose(id int64) uid64.IDComponents {
+	if d, ok := t.g.(interface {
+		Decompose(int64) uid64.IDComponents
+	}); ok {
+		return d.Decompose(id)
+	}
+	return uid64.Decompose(id)
+}
+
+func recordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
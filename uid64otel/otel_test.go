@@ -0,0 +1,90 @@
+package uid64otel_test
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/Ahmed-Sermani/uid64"
+	"github.com/Ahmed-Sermani/uid64/uid64otel"
+	"github.com/Ahmed-Sermani/uid64/uid64test"
+)
+
+func newTracer() (*tracetest.SpanRecorder, *sdktrace.TracerProvider) {
+	rec := tracetest.NewSpanRecorder()
+	return rec, sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+}
+
+func TestWrapGenerator(t *testing.T) {
+	rec, tp := newTracer()
+	gen, err := uid64.NewWithOptions(uid64.WithNodeID(9), uid64.DisableRegistry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	traced := uid64otel.WithTracer(tp.Tracer("test"))(gen)
+
+	id, err := traced.NextID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := traced.NextIDBatch(3); err != nil {
+		t.Fatal(err)
+	}
+	if got := traced.NodeID(); got != 9 {
+		t.Errorf("NodeID = %d, want 9", got)
+	}
+
+	spans := rec.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+	c := uid64.Decompose(int64(id))
+	want := map[attribute.Key]int64{
+		uid64otel.AttributeID:          int64(id),
+		uid64otel.AttributeNodeID:      9,
+		uid64otel.AttributeSequence:    c.Sequence,
+		uid64otel.AttributeTimestampMS: c.Time.UnixNano() / 1e6,
+	}
+	checkAttributes(t, spans[0], "uid64.NextID", want)
+	checkAttributes(t, spans[1], "uid64.NextIDBatch", map[attribute.Key]int64{
+		uid64otel.AttributeNodeID:    9,
+		uid64otel.AttributeBatchSize: 3,
+	})
+}
+
+func TestWrapGeneratorError(t *testing.T) {
+	rec, tp := newTracer()
+	traced := uid64otel.WrapGenerator(uid64test.NewMockGenerator(), tp.Tracer("test"))
+	if _, err := traced.NextID(); err == nil {
+		t.Fatal("NextID of an exhausted mock succeeded")
+	}
+	spans := rec.Ended()
+	if len(spans) != 1 || spans[0].Status().Code != codes.Error {
+		t.Fatalf("spans = %v, want one with an error status", spans)
+	}
+	if attrs := spans[0].Attributes(); len(attrs) != 0 {
+		t.Errorf("failed span has attributes %v, want none", attrs)
+	}
+}
+
+func checkAttributes(t *testing.T, span sdktrace.ReadOnlySpan, name string, want map[attribute.Key]int64) {
+	t.Helper()
+	if span.Name() != name {
+		t.Errorf("span name = %q, want %q", span.Name(), name)
+	}
+	got := make(map[attribute.Key]int64)
+	for _, kv := range span.Attributes() {
+		got[kv.Key] = kv.Value.AsInt64()
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s %s = %d, want %d", name, k, got[k], v)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("%s attributes = %v, want %v", name, got, want)
+	}
+}
@@ -0,0 +1,11 @@
+module github.com/Ahmed-Sermani/uid64/uid64proto
+
+go 1.16
+
+require (
+	github.com/Ahmed-Sermani/uid64 v0.0.0
+	google.golang.org/grpc v1.46.2
+	google.golang.org/protobuf v1.27.1
+)
+
+replace github.com/Ahmed-Sermani/uid64 => ../
@@ -0,0 +1,145 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.26.0
+// 	protoc        v3.19.4
+// source: uid64.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ID is a uid64 ID. The value is kept as int64 rather than a string so it
+// costs at most 9 bytes on the wire.
+type ID struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value int64 `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *ID) Reset() {
+	*x = ID{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_uid64_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ID) ProtoMessage() {}
+
+func (x *ID) ProtoReflect() protoreflect.Message {
+	mi := &file_uid64_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ID.ProtoReflect.Descriptor instead.
+func (*ID) Descriptor() ([]byte, []int) {
+	return file_uid64_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ID) GetValue() int64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+var File_uid64_proto protoreflect.FileDescriptor
+
+var file_uid64_proto_rawDesc = []byte{
+	0x0a, 0x0b, 0x75, 0x69, 0x64, 0x36, 0x34, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x75,
+	0x69, 0x64, 0x36, 0x34, 0x22, 0x1a, 0x0a, 0x02, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x42, 0x2e, 0x5a, 0x2c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x41,
+	0x68, 0x6d, 0x65, 0x64, 0x2d, 0x53, 0x65, 0x72, 0x6d, 0x61, 0x6e, 0x69, 0x2f, 0x75, 0x69, 0x64,
+	0x36, 0x34, 0x2f, 0x75, 0x69, 0x64, 0x36, 0x34, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x70, 0x62,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_uid64_proto_rawDescOnce sync.Once
+	file_uid64_proto_rawDescData = file_uid64_proto_rawDesc
+)
+
+func file_uid64_proto_rawDescGZIP() []byte {
+	file_uid64_proto_rawDescOnce.Do(func() {
+		file_uid64_proto_rawDescData = protoimpl.X.CompressGZIP(file_uid64_proto_rawDescData)
+	})
+	return file_uid64_proto_rawDescData
+}
+
+var file_uid64_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_uid64_proto_goTypes = []interface{}{
+	(*ID)(nil), // 0: uid64.ID
+}
+var file_uid64_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_uid64_proto_init() }
+func file_uid64_proto_init() {
+	if File_uid64_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_uid64_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ID); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_uid64_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_uid64_proto_goTypes,
+		DependencyIndexes: file_uid64_proto_depIdxs,
+		MessageInfos:      file_uid64_proto_msgTypes,
+	}.Build()
+	File_uid64_proto = out.File
+	file_uid64_proto_rawDesc = nil
+	file_uid64_proto_goTypes = nil
+	file_uid64_proto_depIdxs = nil
+}
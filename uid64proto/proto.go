@@ -0,0 +1,71 @@
+// Package uid64proto carries uid64 IDs in protocol buffers, as the ID message
+// defined in pb/uid64.proto.
+package uid64proto
+
+//go:generate protoc -I pb --go_out=pb --go_opt=paths=source_relative uid64.proto
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/Ahmed-Sermani/uid64"
+	"github.com/Ahmed-Sermani/uid64/uid64proto/pb"
+)
+
+// ToProto wraps id in an ID message.
+func ToProto(id int64) *pb.ID {
+	return &pb.ID{Value: id}
+}
+
+// FromProto returns the ID held by p, or 0 if p is nil.
+func FromProto(p *pb.ID) int64 {
+	return p.GetValue()
+}
+
+// Codec implements gRPC's encoding.Codec. It marshals any proto.Message as
+// the standard proto codec does, and in addition lets an int64 or uid64.ID be
+// sent and received directly as an ID message. Register it with
+// encoding.RegisterCodec and select it with the "uid64" content subtype.
+type Codec struct{}
+
+func (Codec) Marshal(v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case int64:
+		return proto.Marshal(ToProto(v))
+	case uid64.ID:
+		return proto.Marshal(ToProto(int64(v)))
+	case proto.Message:
+		return proto.Marshal(v)
+	default:
+		return nil, fmt.Errorf("uid64proto: cannot marshal %T", v)
+	}
+}
+
+func (Codec) Unmarshal(data []byte, v interface{}) error {
+	switch v := v.(type) {
+	case *int64:
+		var p pb.ID
+		if err := proto.Unmarshal(data, &p); err != nil {
+			return err
+		}
+		*v = p.Value
+		return nil
+	case *uid64.ID:
+		var p pb.ID
+		if err := proto.Unmarshal(data, &p); err != nil {
+			return err
+		}
+		*v = uid64.ID(p.Value)
+		return nil
+	case proto.Message:
+		return proto.Unmarshal(data, v)
+	default:
+		return fmt.Errorf("uid64proto: cannot unmarshal into %T", v)
+	}
+}
+
+// Name returns "uid64", the content subtype the codec is registered under.
+func (Codec) Name() string {
+	return "uid64"
+}
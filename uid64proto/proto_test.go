@@ -0,0 +1,70 @@
+package uid64proto_test
+
+import (
+	"bytes"
+	"testing"
+
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/Ahmed-Sermani/uid64"
+	"github.com/Ahmed-Sermani/uid64/uid64proto"
+	"github.com/Ahmed-Sermani/uid64/uid64proto/pb"
+)
+
+var _ encoding.Codec = uid64proto.Codec{}
+
+func TestProtoWireFormat(t *testing.T) {
+	data, err := proto.Marshal(uid64proto.ToProto(300))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Field 1, varint wire type, then 300 in base 128.
+	if want := []byte{0x08, 0xac, 0x02}; !bytes.Equal(data, want) {
+		t.Errorf("wire format = %x, want %x", data, want)
+	}
+
+	const id = int64(1234567890123456789)
+	data, err = proto.Marshal(uid64proto.ToProto(id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p pb.ID
+	if err := proto.Unmarshal(data, &p); err != nil {
+		t.Fatal(err)
+	}
+	if got := uid64proto.FromProto(&p); got != id {
+		t.Errorf("FromProto = %d, want %d", got, id)
+	}
+	if got := uid64proto.FromProto(nil); got != 0 {
+		t.Errorf("FromProto(nil) = %d, want 0", got)
+	}
+}
+
+func TestCodec(t *testing.T) {
+	var c uid64proto.Codec
+	const id = int64(42)
+	for _, v := range []interface{}{id, uid64.ID(id), uid64proto.ToProto(id)} {
+		data, err := c.Marshal(v)
+		if err != nil {
+			t.Fatalf("Marshal(%T): %v", v, err)
+		}
+		var n int64
+		var typed uid64.ID
+		var p pb.ID
+		for _, dst := range []interface{}{&n, &typed, &p} {
+			if err := c.Unmarshal(data, dst); err != nil {
+				t.Fatalf("Unmarshal into %T: %v", dst, err)
+			}
+		}
+		if n != id || typed != uid64.ID(id) || p.Value != id {
+			t.Errorf("round trip of %T = %d, %d, %d, want %d", v, n, typed, p.Value, id)
+		}
+	}
+	if _, err := c.Marshal("42"); err == nil {
+		t.Error("Marshal(string) succeeded, want error")
+	}
+	if err := c.Unmarshal(nil, new(string)); err == nil {
+		t.Error("Unmarshal into *string succeeded, want error")
+	}
+}
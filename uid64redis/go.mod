@@ -0,0 +1,8 @@
+module github.com/Ahmed-Sermani/uid64/uid64redis
+
+go 1.16
+
+require (
+	github.com/alicebob/miniredis/v2 v2.14.5
+	github.com/go-redis/redis/v8 v8.11.4
+)
@@ -0,0 +1,78 @@
+// Package uid64redis coordinates node IDs across a cluster through Redis, so
+// that every instance of a service claims a node ID no other instance holds.
+package uid64redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// maxNodeID is the largest node ID of the default uid64 bit layout.
+const maxNodeID = 1<<10 - 1
+
+var (
+	ErrNoFreeNodeID = errors.New("every node ID is claimed")
+	ErrLeaseLost    = errors.New("node ID lease expired or was claimed by another instance")
+)
+
+// owner identifies this process as the holder of the slots it claims.
+var owner = fmt.Sprintf("%s-%d-%d", hostname(), os.Getpid(), rand.New(rand.NewSource(time.Now().UnixNano())).Int63())
+
+// renewScript extends a slot's TTL only while it still holds our owner value.
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// NodeFromRedis claims the lowest free node ID by setting the key prefix
+// followed by the node ID with SETNX, expiring after ttl. Once the key
+// expires another instance may claim the same node ID, so callers must call
+// RenewNodeLease well within ttl for as long as they generate IDs. It returns
+// ErrNoFreeNodeID if all 1024 node IDs are claimed.
+func NodeFromRedis(ctx context.Context, client *redis.Client, prefix string, ttl time.Duration) (int, error) {
+	for nodeID := 0; nodeID <= maxNodeID; nodeID++ {
+		ok, err := client.SetNX(ctx, key(prefix, nodeID), owner, ttl).Result()
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			return nodeID, nil
+		}
+	}
+	return 0, ErrNoFreeNodeID
+}
+
+// RenewNodeLease resets the expiry of the node ID claimed by NodeFromRedis to
+// ttl. It returns ErrLeaseLost if the claim has expired or another process
+// holds it, in which case the caller must stop generating IDs with nodeID.
+func RenewNodeLease(ctx context.Context, client *redis.Client, prefix string, nodeID int, ttl time.Duration) error {
+	n, err := renewScript.Run(ctx, client, []string{key(prefix, nodeID)}, owner, ttl.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+func key(prefix string, nodeID int) string {
+	return prefix + strconv.Itoa(nodeID)
+}
+
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+	return name
+}
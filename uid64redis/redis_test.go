@@ -0,0 +1,84 @@
+package uid64redis_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+
+	"github.com/Ahmed-Sermani/uid64/uid64redis"
+)
+
+func newClient(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(mr.Close)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return mr, client
+}
+
+func TestNodeFromRedis(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newClient(t)
+
+	for want := 0; want < 3; want++ {
+		got, err := uid64redis.NodeFromRedis(ctx, client, "uid64:node:", time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("NodeFromRedis = %d, want %d", got, want)
+		}
+	}
+	if ttl := mr.TTL("uid64:node:1"); ttl != time.Minute {
+		t.Errorf("TTL = %v, want 1m", ttl)
+	}
+
+	mr.FastForward(2 * time.Minute)
+	if got, err := uid64redis.NodeFromRedis(ctx, client, "uid64:node:", time.Minute); err != nil || got != 0 {
+		t.Errorf("NodeFromRedis after expiry = %d, %v, want 0", got, err)
+	}
+}
+
+func TestNodeFromRedisFull(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newClient(t)
+	for i := 0; i < 1024; i++ {
+		mr.Set("full:"+strconv.Itoa(i), "someone")
+	}
+	if _, err := uid64redis.NodeFromRedis(ctx, client, "full:", time.Minute); err != uid64redis.ErrNoFreeNodeID {
+		t.Errorf("err = %v, want ErrNoFreeNodeID", err)
+	}
+}
+
+func TestRenewNodeLease(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newClient(t)
+	nodeID, err := uid64redis.NodeFromRedis(ctx, client, "p:", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mr.FastForward(50 * time.Second)
+	if err := uid64redis.RenewNodeLease(ctx, client, "p:", nodeID, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := mr.TTL("p:0"); ttl != time.Hour {
+		t.Errorf("TTL after renew = %v, want 1h", ttl)
+	}
+
+	mr.FastForward(2 * time.Hour)
+	if err := uid64redis.RenewNodeLease(ctx, client, "p:", nodeID, time.Hour); err != uid64redis.ErrLeaseLost {
+		t.Errorf("renew after expiry: err = %v, want ErrLeaseLost", err)
+	}
+	mr.Set("p:0", "another instance")
+	if err := uid64redis.RenewNodeLease(ctx, client, "p:", nodeID, time.Hour); err != uid64redis.ErrLeaseLost {
+		t.Errorf("renew of a slot claimed elsewhere: err = %v, want ErrLeaseLost", err)
+	}
+}
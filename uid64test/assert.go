@@ -0,0 +1,70 @@
+package uid64test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+// AssertMonotonic fails t unless ids are strictly increasing.
+func AssertMonotonic(t testing.TB, ids []int64) {
+	t.Helper()
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Errorf("ids[%d] = %d is not greater than ids[%d] = %d", i, ids[i], i-1, ids[i-1])
+			return
+		}
+	}
+}
+
+// AssertNodeID fails t unless id carries the node ID expected.
+func AssertNodeID(t testing.TB, id int64, expected int) {
+	t.Helper()
+	if got := uid64.NodeIDOf(id); got != expected {
+		t.Errorf("node ID of %d = %d, want %d", id, got, expected)
+	}
+}
+
+// AssertUnique calls g.NextID idsPerGoroutine times from each of goroutines
+// goroutines at once and fails t if any call fails or any ID repeats. Run it
+// under -race to check g for data races as well.
+func AssertUnique(t testing.TB, g uid64.IDGenerator, goroutines, idsPerGoroutine int) {
+	t.Helper()
+	results := make([][]int64, goroutines)
+	errs := make([]error, goroutines)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ids := make([]int64, 0, idsPerGoroutine)
+			for j := 0; j < idsPerGoroutine; j++ {
+				id, err := g.NextID()
+				if err != nil {
+					errs[i] = err
+					break
+				}
+				ids = append(ids, int64(id))
+			}
+			results[i] = ids
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("goroutine %d: NextID: %v", i, err)
+		}
+	}
+	seen := make(map[int64]int, goroutines*idsPerGoroutine)
+	for i, ids := range results {
+		for _, id := range ids {
+			if prev, ok := seen[id]; ok {
+				t.Errorf("ID %d returned to goroutines %d and %d", id, prev, i)
+				return
+			}
+			seen[id] = i
+		}
+	}
+}
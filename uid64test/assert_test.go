@@ -0,0 +1,54 @@
+package uid64test_test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Ahmed-Sermani/uid64"
+	"github.com/Ahmed-Sermani/uid64/uid64test"
+)
+
+func TestConcurrentUniqueness(t *testing.T) {
+	perGoroutine := 100000
+	if testing.Short() {
+		perGoroutine = 10000
+	}
+	gen, err := uid64.NewWithOptions(uid64.WithNodeID(7), uid64.DisableRegistry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	uid64test.AssertUnique(t, gen, 16, perGoroutine)
+}
+
+// recordingTB is a testing.TB that records failures instead of failing the
+// test. Methods the assertions do not call are left to the embedded nil
+// interface and panic.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertUniqueReportsFailures(t *testing.T) {
+	// One goroutine asks for four IDs; the third repeats the first and the
+	// fourth call fails with io.EOF.
+	gen := uid64test.NewMockGenerator(1, 2, 1)
+	tb := &recordingTB{}
+	uid64test.AssertUnique(tb, gen, 1, 4)
+
+	var duplicate, failed bool
+	for _, msg := range tb.errors {
+		duplicate = duplicate || strings.Contains(msg, "ID 1 returned to goroutines 0 and 0")
+		failed = failed || strings.Contains(msg, "NextID: "+io.EOF.Error())
+	}
+	if !duplicate || !failed {
+		t.Errorf("AssertUnique reported %q, want the duplicate ID and the NextID error", tb.errors)
+	}
+}
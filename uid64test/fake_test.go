@@ -0,0 +1,45 @@
+package uid64test_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ahmed-Sermani/uid64"
+	"github.com/Ahmed-Sermani/uid64/uid64test"
+)
+
+func TestFakeGenerator(t *testing.T) {
+	start := time.Date(2024, 3, 15, 10, 23, 45, 123e6, time.UTC)
+	gen, err := uid64test.NewFakeGenerator(start, uid64.WithNodeID(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []int64
+	for i := 0; i < 3; i++ {
+		id, err := gen.NextInt64()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+		gen.Clock.Advance(time.Millisecond)
+	}
+	uid64test.AssertMonotonic(t, ids)
+	uid64test.AssertNodeID(t, ids[0], 42)
+	if got := uid64.TimeOf(ids[0]); !got.Equal(start) {
+		t.Errorf("TimeOf(first) = %v, want %v", got, start)
+	}
+	if got := uid64.TimeOf(ids[2]); !got.Equal(start.Add(2 * time.Millisecond)) {
+		t.Errorf("TimeOf(third) = %v, want %v", got, start.Add(2*time.Millisecond))
+	}
+
+	later := start.Add(time.Hour)
+	gen.SetTime(later)
+	id, err := gen.NextInt64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := uid64.TimeOf(id); !got.Equal(later) {
+		t.Errorf("TimeOf after SetTime = %v, want %v", got, later)
+	}
+}
@@ -0,0 +1,55 @@
+// Package uid64test provides test doubles and helpers for code that uses uid64.
+package uid64test
+
+import (
+	"io"
+	"sync"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+type mockGenerator struct {
+	mu     sync.Mutex
+	ids    []int64
+	nodeID int
+}
+
+// NewMockGenerator returns an IDGenerator that hands out ids in order and
+// then fails with io.EOF. Its NodeID is the node ID of the first of ids, or 0.
+func NewMockGenerator(ids ...int64) uid64.IDGenerator {
+	m := &mockGenerator{ids: append([]int64(nil), ids...)}
+	if len(ids) > 0 {
+		m.nodeID = uid64.NodeIDOf(ids[0])
+	}
+	return m
+}
+
+func (m *mockGenerator) NextID() (uid64.ID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.ids) == 0 {
+		return 0, io.EOF
+	}
+	id := m.ids[0]
+	m.ids = m.ids[1:]
+	return uid64.ID(id), nil
+}
+
+// NextIDBatch fails with io.EOF, consuming nothing, if fewer than n IDs remain.
+func (m *mockGenerator) NextIDBatch(n int) ([]int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if n > len(m.ids) {
+		return nil, io.EOF
+	}
+	if n <= 0 {
+		return nil, nil
+	}
+	ids := append([]int64(nil), m.ids[:n]...)
+	m.ids = m.ids[n:]
+	return ids, nil
+}
+
+func (m *mockGenerator) NodeID() int {
+	return m.nodeID
+}
@@ -0,0 +1,30 @@
+package uid64test_test
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/Ahmed-Sermani/uid64/uid64test"
+)
+
+func TestMockGenerator(t *testing.T) {
+	gen := uid64test.NewMockGenerator(5<<12|1, 5<<12|2, 5<<12|3)
+	if got := gen.NodeID(); got != 5 {
+		t.Errorf("NodeID = %d, want 5", got)
+	}
+	id, err := gen.NextID()
+	if err != nil || id != 5<<12|1 {
+		t.Errorf("NextID = %d, %v, want %d", id, err, 5<<12|1)
+	}
+	if _, err := gen.NextIDBatch(3); err != io.EOF {
+		t.Errorf("NextIDBatch(3) err = %v, want io.EOF", err)
+	}
+	ids, err := gen.NextIDBatch(2)
+	if err != nil || !reflect.DeepEqual(ids, []int64{5<<12 | 2, 5<<12 | 3}) {
+		t.Errorf("NextIDBatch(2) = %v, %v", ids, err)
+	}
+	if _, err := gen.NextID(); err != io.EOF {
+		t.Errorf("NextID when exhausted: err = %v, want io.EOF", err)
+	}
+}
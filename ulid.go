@@ -0,0 +1,56 @@
+package uid64
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/Ahmed-Sermani/uid64/internal/bits"
+)
+
+var ErrInvalidULID = errors.New("not a ULID produced from a uid64 ID")
+
+// ToULID encodes id as a ULID. The 48-bit ULID time is the Unix time of id in
+// milliseconds, assuming the default epoch, and the node ID and sequence fill
+// the top 22 bits of the 80-bit random part; the rest is zero. ULIDs made this
+// way sort in the same order as their IDs.
+func ToULID(id int64) [16]byte {
+	unixMs := uint64(defaultLayout.timestamp(id) + customEpoch)
+	low := uint64(bits.Low(id, defaultLayout.Layout))
+
+	var b [16]byte
+	// 48 bits of time, then the 22 low bits of id, then zeros.
+	binary.BigEndian.PutUint64(b[:8], unixMs<<16|low>>6)
+	binary.BigEndian.PutUint64(b[8:], (low&0x3f)<<58)
+	return b
+}
+
+// ULIDString returns the 26 character Crockford base32 form of ToULID(id).
+func ULIDString(id int64) string {
+	b := ToULID(id)
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+
+	var s [26]byte
+	for i := len(s) - 1; i >= 0; i-- {
+		s[i] = base32Alphabet[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(s[:])
+}
+
+// FromULID reverses ToULID. It returns ErrInvalidULID if b could not have been
+// produced by ToULID.
+func FromULID(b [16]byte) (int64, error) {
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+	if lo&(1<<58-1) != 0 {
+		return 0, ErrInvalidULID
+	}
+	ts := int64(hi>>16) - customEpoch
+	if ts < 0 || ts > maxTimestamp {
+		return 0, ErrInvalidULID
+	}
+	low := (hi&0xffff)<<6 | lo>>58
+	return bits.Pack(ts, 0, 0, defaultLayout.Layout) | int64(low), nil
+}
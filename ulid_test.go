@@ -0,0 +1,61 @@
+package uid64_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestULID(t *testing.T) {
+	ids, err := g.NextIDBatch(4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids = append([]int64{0, 1<<22 - 1}, ids...)
+
+	for i, id := range ids {
+		b := uid64.ToULID(id)
+		got, err := uid64.FromULID(b)
+		if err != nil || got != id {
+			t.Fatalf("FromULID(ToULID(%d)) = %d, %v", id, got, err)
+		}
+		if ms := binary.BigEndian.Uint64(append([]byte{0, 0}, b[:6]...)); int64(ms) != uid64.TimeOf(id).UnixNano()/1e6 {
+			t.Fatalf("ULID time of %d = %d, want %v", id, ms, uid64.TimeOf(id))
+		}
+		s := uid64.ULIDString(id)
+		if len(s) != 26 || s[0] > '7' {
+			t.Fatalf("ULIDString(%d) = %q is not a ULID", id, s)
+		}
+		if i == 0 {
+			continue
+		}
+		prev := uid64.ToULID(ids[i-1])
+		if bytes.Compare(prev[:], b[:]) >= 0 {
+			t.Fatalf("ToULID(%d) not less than ToULID(%d)", ids[i-1], id)
+		}
+		if uid64.ULIDString(ids[i-1]) >= s {
+			t.Fatalf("ULIDString(%d) not less than ULIDString(%d)", ids[i-1], id)
+		}
+	}
+}
+
+func TestULIDString(t *testing.T) {
+	// 2015-01-01T00:00:00Z is 0x014AA2CAB000 ms after the Unix epoch, and a
+	// node ID and sequence of all ones set the top 22 random bits.
+	if got, want := uid64.ULIDString(1<<22-1), "019AHCNC00ZZZZR00000000000"; got != want {
+		t.Errorf("ULIDString = %q, want %q", got, want)
+	}
+}
+
+func TestFromULIDInvalid(t *testing.T) {
+	b := uid64.ToULID(1 << 22)
+	b[15] = 1
+	if _, err := uid64.FromULID(b); err != uid64.ErrInvalidULID {
+		t.Errorf("trailing bits set: err = %v, want ErrInvalidULID", err)
+	}
+	if _, err := uid64.FromULID([16]byte{}); err != uid64.ErrInvalidULID {
+		t.Errorf("before the epoch: err = %v, want ErrInvalidULID", err)
+	}
+}
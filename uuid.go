@@ -0,0 +1,62 @@
+package uid64
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+
+	"github.com/Ahmed-Sermani/uid64/internal/bits"
+)
+
+var ErrInvalidUUID = errors.New("not a UUIDv7 produced from a uid64 ID")
+
+// ToUUIDv7 formats id as an RFC 9562 version 7 UUID. The UUID timestamp is the
+// Unix time of id in milliseconds, assuming the default epoch, and the node ID
+// and sequence fill the 22 bits that follow the version and variant fields;
+// the remaining bits are zero. UUIDs made this way sort as strings in the same
+// order as their IDs.
+func ToUUIDv7(id int64) string {
+	unixMs := uint64(defaultLayout.timestamp(id) + customEpoch)
+	low := uint64(bits.Low(id, defaultLayout.Layout))
+
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], unixMs<<16|0x7<<12|low>>10)
+	binary.BigEndian.PutUint64(b[8:], 0x2<<62|(low&0x3ff)<<52)
+
+	var s [36]byte
+	hex.Encode(s[0:8], b[0:4])
+	s[8] = '-'
+	hex.Encode(s[9:13], b[4:6])
+	s[13] = '-'
+	hex.Encode(s[14:18], b[6:8])
+	s[18] = '-'
+	hex.Encode(s[19:23], b[8:10])
+	s[23] = '-'
+	hex.Encode(s[24:], b[10:])
+	return string(s[:])
+}
+
+// FromUUIDv7 reverses ToUUIDv7. It returns ErrInvalidUUID if s is not in the
+// canonical 8-4-4-4-12 hex form or is not a UUID that ToUUIDv7 could produce.
+func FromUUIDv7(s string) (int64, error) {
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return 0, ErrInvalidUUID
+	}
+	var b [16]byte
+	digits := s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	if _, err := hex.Decode(b[:], []byte(digits)); err != nil {
+		return 0, ErrInvalidUUID
+	}
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+	if hi>>12&0xf != 0x7 || lo>>62 != 0x2 || lo&(1<<52-1) != 0 {
+		return 0, ErrInvalidUUID
+	}
+
+	ts := int64(hi>>16) - customEpoch
+	if ts < 0 || ts > maxTimestamp {
+		return 0, ErrInvalidUUID
+	}
+	low := (hi&0xfff)<<10 | lo>>52&0x3ff
+	return bits.Pack(ts, 0, 0, defaultLayout.Layout) | int64(low), nil
+}
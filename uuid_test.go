@@ -0,0 +1,57 @@
+package uid64_test
+
+import (
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+var uuidv7Pattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestUUIDv7(t *testing.T) {
+	ids, err := g.NextIDBatch(4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids = append(ids, 1<<22-1, 1<<22)
+
+	uuids := make([]string, len(ids))
+	for i, id := range ids {
+		u := uid64.ToUUIDv7(id)
+		if !uuidv7Pattern.MatchString(u) {
+			t.Fatalf("ToUUIDv7(%d) = %q is not a UUIDv7", id, u)
+		}
+		got, err := uid64.FromUUIDv7(u)
+		if err != nil || got != id {
+			t.Fatalf("FromUUIDv7(%q) = %d, %v, want %d", u, got, err, id)
+		}
+		uuids[i] = u
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	sort.Strings(uuids)
+	for i, id := range ids {
+		if got, _ := uid64.FromUUIDv7(uuids[i]); got != id {
+			t.Fatalf("UUIDs sort differently from IDs at %d", i)
+		}
+	}
+}
+
+func TestFromUUIDv7Invalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"0189a4f1-04c0-7000-8000-000000000000x",
+		"0189a4f104c0-7000-8000-0000000000000",
+		"0189a4f1-04c0-4000-8000-000000000000",
+		"0189a4f1-04c0-7000-c000-000000000000",
+		"0189a4f1-04c0-7000-8000-000000000001",
+		"0189a4f1-04c0-7000-8000-00000000000g",
+		"00000000-0000-7000-8000-000000000000",
+	} {
+		if _, err := uid64.FromUUIDv7(s); err != uid64.ErrInvalidUUID {
+			t.Errorf("FromUUIDv7(%q): err = %v, want ErrInvalidUUID", s, err)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package uid64
+
+import (
+	"errors"
+	"time"
+)
+
+// futureTolerance is how far past the current time a timestamp may lie before
+// ValidateID treats it as corrupt, allowing for clock skew between hosts.
+const futureTolerance = 5 * time.Second
+
+var (
+	ErrNegativeID = errors.New("ID has the sign bit set")
+	// ErrTimestampTooOld reports a timestamp before the epoch. With the sign
+	// bit clear the 41-bit timestamp cannot be negative, so ValidateID reports
+	// such IDs as ErrNegativeID instead.
+	ErrTimestampTooOld   = errors.New("ID timestamp is before the epoch")
+	ErrTimestampInFuture = errors.New("ID timestamp is in the future")
+)
+
+// ValidateID checks that id could have been produced by a generator with the
+// default epoch and bit layout: the sign bit must be clear and the timestamp
+// no more than a few seconds ahead of the current time. The node ID and
+// sequence fields cannot overflow, since they are read by masking.
+func ValidateID(id int64) error {
+	if id < 0 {
+		return ErrNegativeID
+	}
+	if defaultLayout.timestamp(id) > unixMilli(time.Now().Add(futureTolerance))-customEpoch {
+		return ErrTimestampInFuture
+	}
+	return nil
+}
+
+// IsValid reports whether ValidateID accepts id.
+func IsValid(id int64) bool {
+	return ValidateID(id) == nil
+}
@@ -0,0 +1,37 @@
+package uid64_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestValidateID(t *testing.T) {
+	id, err := g.NextInt64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	future, _, err := uid64.IDsInTimeRange(time.Now().Add(time.Minute), time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		id  int64
+		err error
+	}{
+		{id, nil},
+		{0, nil},
+		{-1, uid64.ErrNegativeID},
+		{-id, uid64.ErrNegativeID},
+		{future, uid64.ErrTimestampInFuture},
+	} {
+		if err := uid64.ValidateID(tc.id); err != tc.err {
+			t.Errorf("ValidateID(%d) = %v, want %v", tc.id, err, tc.err)
+		}
+		if got := uid64.IsValid(tc.id); got != (tc.err == nil) {
+			t.Errorf("IsValid(%d) = %v, want %v", tc.id, got, tc.err == nil)
+		}
+	}
+}
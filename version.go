@@ -0,0 +1,40 @@
+package uid64
+
+import "math"
+
+// v1Layout is the layout of version 1 IDs: the default split with the node ID
+// divided into 5 datacenter bits and 5 worker bits, as NewWithDatacenterWorker
+// does by default.
+var v1Layout = func() layout {
+	l := newLayout(nodeIDBits, sequenceBits)
+	l.datacenterBits, l.workerBits = 5, 5
+	return l
+}()
+
+// EmbedVersion returns id with bit 63 set to the low bit of version, leaving
+// the other bits alone. The bit marks which layout an ID was built
+// with when a deployment moves from one to another: 0 for the original node
+// ID layout, 1 for the datacenter and worker layout. Version 1 IDs are
+// negative as int64 values, with the consequences described at WithTypeTag,
+// which uses the same bit; the two schemes cannot be combined.
+func EmbedVersion(id int64, version uint8) int64 {
+	if version&1 == 0 {
+		return id &^ math.MinInt64
+	}
+	return id | math.MinInt64
+}
+
+// VersionOf returns the version stored in id by EmbedVersion.
+func VersionOf(id int64) uint8 {
+	return uint8(uint64(id) >> 63)
+}
+
+// DecodeV0 decomposes a version 0 ID, as Decompose does.
+func DecodeV0(id int64) IDComponents {
+	return decompose(id, customEpoch)
+}
+
+// DecodeV1 decomposes a version 1 ID, reporting its datacenter and worker IDs.
+func DecodeV1(id int64) IDComponents {
+	return decomposeLayout(id, customEpoch, v1Layout)
+}
@@ -0,0 +1,38 @@
+package uid64_test
+
+import (
+	"testing"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestEmbedVersion(t *testing.T) {
+	gen, err := uid64.NewWithDatacenterWorker(3, 17, uid64.WithClock(func() int64 { return 1000 }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+	id, err := gen.NextInt64()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v1 := uid64.EmbedVersion(id, 1)
+	if uid64.VersionOf(v1) != 1 || v1 >= 0 {
+		t.Errorf("EmbedVersion(id, 1) = %d, VersionOf = %d, want a negative version 1 ID", v1, uid64.VersionOf(v1))
+	}
+	if c := uid64.DecodeV1(v1); c.Timestamp != 1000 || c.DatacenterID != 3 || c.WorkerID != 17 || c.NodeID != 3<<5|17 {
+		t.Errorf("DecodeV1 = %+v, want timestamp 1000 datacenter 3 worker 17", c)
+	}
+
+	v0 := uid64.EmbedVersion(v1, 0)
+	if v0 != id || uid64.VersionOf(v0) != 0 {
+		t.Errorf("EmbedVersion(v1, 0) = %d, want %d", v0, id)
+	}
+	if c := uid64.DecodeV0(v0); c != uid64.Decompose(id) {
+		t.Errorf("DecodeV0 = %+v, want %+v", c, uid64.Decompose(id))
+	}
+	if got := uid64.EmbedVersion(uid64.EmbedVersion(id, 1), 1); got != v1 {
+		t.Errorf("EmbedVersion is not idempotent: %d, want %d", got, v1)
+	}
+}
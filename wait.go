@@ -0,0 +1,64 @@
+package uid64
+
+import (
+	"context"
+	"runtime"
+	"time"
+)
+
+// WaitStrategy decides how NextID passes the time while waiting for the clock
+// to move on, after the sequence is exhausted or within the clock drift
+// tolerance. Wait is called repeatedly until the clock has advanced and should
+// return ctx.Err() once ctx is done.
+type WaitStrategy interface {
+	Wait(ctx context.Context) error
+}
+
+// WaitStrategyFunc adapts an ordinary function to a WaitStrategy.
+type WaitStrategyFunc func(ctx context.Context) error
+
+func (f WaitStrategyFunc) Wait(ctx context.Context) error {
+	return f(ctx)
+}
+
+var (
+	// SpinWaitStrategy polls the clock in a tight loop. It reacts fastest but
+	// keeps a core busy while waiting. It is the default.
+	SpinWaitStrategy WaitStrategy = WaitStrategyFunc(spinWait)
+	// YieldWaitStrategy polls the clock but yields the processor to other
+	// goroutines between polls.
+	YieldWaitStrategy WaitStrategy = WaitStrategyFunc(yieldWait)
+)
+
+// SleepWaitStrategy sleeps for interval between polls of the clock, trading
+// up to interval of latency for an idle core.
+func SleepWaitStrategy(interval time.Duration) WaitStrategy {
+	return WaitStrategyFunc(func(ctx context.Context) error {
+		t := time.NewTimer(interval)
+		defer t.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			return nil
+		}
+	})
+}
+
+// WithWaitStrategy sets how the generator waits for the clock to move on. The
+// default is SpinWaitStrategy.
+func WithWaitStrategy(ws WaitStrategy) GeneratorOption {
+	return func(g *Generator) error {
+		g.waitStrategy = ws
+		return nil
+	}
+}
+
+func spinWait(ctx context.Context) error {
+	return ctx.Err()
+}
+
+func yieldWait(ctx context.Context) error {
+	runtime.Gosched()
+	return ctx.Err()
+}
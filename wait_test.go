@@ -0,0 +1,64 @@
+package uid64_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestWithWaitStrategy(t *testing.T) {
+	clock := &fakeClock{now: 1000}
+	var waits int
+	gen, err := uid64.NewWithOptions(
+		uid64.WithNodeID(1),
+		uid64.WithClock(clock.Now),
+		uid64.WithWaitStrategy(uid64.WaitStrategyFunc(func(ctx context.Context) error {
+			waits++
+			if waits == 3 {
+				clock.now++
+			}
+			return nil
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+	for i := 0; i < 1<<12; i++ {
+		if _, err := gen.NextID(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	id, err := gen.NextInt64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if waits != 3 {
+		t.Errorf("Wait called %d times, want 3", waits)
+	}
+	if c := uid64.Decompose(id); c.Timestamp != 1001 || c.Sequence != 0 {
+		t.Errorf("Decompose = %+v, want timestamp 1001 sequence 0", c)
+	}
+}
+
+func TestWaitStrategiesCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for name, ws := range map[string]uid64.WaitStrategy{
+		"spin":  uid64.SpinWaitStrategy,
+		"yield": uid64.YieldWaitStrategy,
+		"sleep": uid64.SleepWaitStrategy(time.Hour),
+	} {
+		if err := ws.Wait(ctx); err != context.Canceled {
+			t.Errorf("%s: err = %v, want context.Canceled", name, err)
+		}
+		if name == "sleep" {
+			ws = uid64.SleepWaitStrategy(time.Millisecond)
+		}
+		if err := ws.Wait(context.Background()); err != nil {
+			t.Errorf("%s: err = %v, want nil", name, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package uid64_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Ahmed-Sermani/uid64"
+)
+
+func TestToWideID(t *testing.T) {
+	id := int64(1000)<<22 | 42<<12 | 7
+	w := uid64.ToWideID(id)
+	if want := uid64.TimeOf(id).UnixNano() / 1e6; w.Hi != want || w.Lo != 42<<12|7 {
+		t.Errorf("ToWideID = %+v, want {Hi:%d Lo:%d}", w, want, 42<<12|7)
+	}
+	if got, want := w.String(), "0000014aa2cab3e8000000000002a007"; got != want {
+		t.Errorf("String = %q, want %q", got, want)
+	}
+}
+
+func TestWideIDCompare(t *testing.T) {
+	for _, tc := range []struct {
+		a, b uid64.WideID
+		want int
+	}{
+		{uid64.WideID{1, 2}, uid64.WideID{1, 2}, 0},
+		{uid64.WideID{1, 2}, uid64.WideID{1, 3}, -1},
+		{uid64.WideID{2, 0}, uid64.WideID{1, math.MaxInt64}, 1},
+		{uid64.WideID{1, 5}, uid64.WideID{2, 0}, -1},
+	} {
+		if got := tc.a.Compare(tc.b); got != tc.want {
+			t.Errorf("%v.Compare(%v) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestWideGenerator(t *testing.T) {
+	// Far past the 2084 overflow of the 41 bit timestamp field.
+	clock := &fakeClock{now: 1 << 45}
+	gen, err := uid64.NewWideGenerator(uid64.WithNodeID(9), uid64.WithClock(clock.Now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gen.Close()
+
+	var last uid64.WideID
+	for i := 0; i < 5000; i++ {
+		if i == 4096 {
+			clock.now++
+		}
+		w, err := gen.NextWideID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i > 0 && w.Compare(last) <= 0 {
+			t.Fatalf("%v is not after %v", w, last)
+		}
+		last = w
+	}
+	if want := int64(1<<45+1) + uid64.DefaultEpoch().UnixNano()/1e6; last.Hi != want {
+		t.Errorf("Hi = %d, want %d", last.Hi, want)
+	}
+	if want := int64(9<<12 | (5000 - 4096 - 1)); last.Lo != want {
+		t.Errorf("Lo = %d, want %d", last.Lo, want)
+	}
+}